@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"RemindGo/internal/database"
+	"RemindGo/internal/importer"
+	"RemindGo/internal/model"
+	"RemindGo/internal/service"
+)
+
+// runImport 导入子命令：将导出文件导入到指定用户，返回进程退出码
+//
+//	server import -user alice -format todoist [-dry-run] 工作.csv
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	username := fs.String("user", "", "导入到的用户（用户名或邮箱）")
+	format := fs.String("format", "", "文件格式: json, csv, ics, markdown, "+strings.Join(importer.Names(), ", "))
+	dryRun := fs.Bool("dry-run", false, "只校验，不写入数据")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法: server import -user <用户> -format <格式> [-dry-run] <文件>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *username == "" || *format == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	db, err := database.InitDB(databaseConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "初始化数据库失败: %v\n", err)
+		return 1
+	}
+	// 与服务端共用缓存和变更通知，导入后使缓存失效并推送给在线的客户端
+	redisClient := newRedis()
+	store := newCache(redisClient)
+	userService := service.NewUserService(db, store)
+	todoService := service.NewTodoService(db, store, newBroker(redisClient))
+
+	user, err := userService.GetUserByUsername(*username)
+	if err != nil {
+		user, err = userService.GetUserByEmail(*username)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "用户 %s 不存在\n", *username)
+		return 1
+	}
+
+	// 逐个文件导入，Todoist 的每个清单是一个 CSV 文件
+	code := 0
+	for _, path := range fs.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			code = 1
+			continue
+		}
+		result, err := todoService.ImportTodos(user.ID, *format, filepath.Base(path), data, *dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			code = 1
+			continue
+		}
+		printImportResult(path, result)
+		if len(result.Errors) > 0 {
+			code = 1
+		}
+	}
+	return code
+}
+
+// printImportResult 输出导入结果，明细以 JSON 格式输出
+func printImportResult(path string, result *model.ImportResult) {
+	action := "已导入"
+	if result.DryRun {
+		action = "将导入"
+	}
+	fmt.Printf("%s: 共 %d 条，%s %d 条，重复 %d 条，新建清单 %d 个\n",
+		path, result.Total, action, result.Created, result.Duplicates, result.ProjectsCreated)
+	if len(result.Errors) == 0 && len(result.Skipped) == 0 && len(result.Warnings) == 0 {
+		return
+	}
+	details, _ := json.MarshalIndent(map[string][]model.ImportError{
+		"errors":   result.Errors,
+		"skipped":  result.Skipped,
+		"warnings": result.Warnings,
+	}, "", "  ")
+	fmt.Println(string(details))
+}
@@ -3,10 +3,16 @@ package main
 import (
 	"context"
 	"log"
+	"os"
+	"strconv"
+	"time"
 
+	"RemindGo/internal/cache"
 	"RemindGo/internal/database"
 	"RemindGo/internal/handler"
+	"RemindGo/internal/mail"
 	"RemindGo/internal/middleware"
+	"RemindGo/internal/realtime"
 	"RemindGo/internal/router"
 	"RemindGo/internal/service"
 
@@ -14,31 +20,141 @@ import (
 	"github.com/cloudwego/hertz/pkg/app/server"
 	"github.com/cloudwego/hertz/pkg/common/utils"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/redis/go-redis/v9"
 )
 
-func main() {
-	// 初始化数据库
-	// 注意：这里使用的是示例配置，生产环境应该从配置文件或环境变量读取
-	dbConfig := database.Config{
+// databaseConfig 数据库配置
+// 注意：这里使用的是示例配置，生产环境应该从配置文件或环境变量读取
+func databaseConfig() database.Config {
+	return database.Config{
 		Host:     "localhost",
 		Port:     3306,
 		User:     "root",
 		Password: "123456",
 		DBName:   "remind_go",
 	}
+}
+
+// newRedis 连接 Redis，连接失败时返回 nil
+func newRedis() *redis.Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:     "localhost:6379",
+		Password: "123456",
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("Redis unavailable (%v), falling back to in-process cache and notifications", err)
+		client.Close()
+		return nil
+	}
+	return client
+}
+
+// newCache 创建缓存，没有 Redis 时使用进程内 LRU 缓存
+func newCache(client *redis.Client) *cache.Store {
+	if client == nil {
+		return cache.New(cache.NewLRU(10000), "remindgo:")
+	}
+	return cache.New(cache.NewRedis(client), "remindgo:")
+}
+
+// newBroker 创建变更通知，有 Redis 时通过发布/订阅在多个实例之间转发
+func newBroker(client *redis.Client) *realtime.Broker {
+	if client == nil {
+		return realtime.NewBroker()
+	}
+	return realtime.NewRedisBroker(client, "remindgo:changes")
+}
+
+// publicBaseURL 对外访问地址，用于摘要邮件中的链接
+const publicBaseURL = "http://localhost:8080"
+
+// newMailSender 创建邮件发送器，未配置 SMTP 服务器时只记录日志
+func newMailSender() mail.Sender {
+	smtp := &mail.SMTP{
+		Host:     "",
+		Port:     587,
+		Username: "",
+		Password: "",
+		From:     "RemindGo <noreply@example.com>",
+	}
+	if smtp.Host == "" {
+		log.Println("SMTP not configured, digest emails will only be logged")
+		return mail.Log{}
+	}
+	return smtp
+}
+
+// envInt 读取整数环境变量，未设置、格式错误或小于 min 时使用默认值
+func envInt(name string, fallback, min int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min {
+		log.Printf("Invalid %s %q, using default %d", name, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+// configureRetention 从环境变量读取回收站和版本历史的保留设置，未设置时保持默认值
+func configureRetention() {
+	service.TrashRetentionDays = envInt("REMINDGO_TRASH_RETENTION_DAYS", service.TrashRetentionDays, 1)
+	service.MaxRevisionsPerTodo = envInt("REMINDGO_MAX_REVISIONS_PER_TODO", service.MaxRevisionsPerTodo, 0)
+}
+
+func main() {
+	// 子命令
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
 
-	db, err := database.InitDB(dbConfig)
+	configureRetention()
+
+	// 初始化数据库
+	db, err := database.InitDB(databaseConfig())
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
+	// 初始化缓存和变更通知
+	redisClient := newRedis()
+	store := newCache(redisClient)
+	broker := newBroker(redisClient)
+	go broker.Run(context.Background())
+
 	// 初始化Service层
-	userService := service.NewUserService(db)
-	todoService := service.NewTodoService(db)
+	userService := service.NewUserService(db, store)
+	todoService := service.NewTodoService(db, store, broker)
+	projectService := service.NewProjectService(db, store, broker)
+	calendarService := service.NewCalendarService(db)
+	digestService := service.NewDigestService(db, newMailSender(), publicBaseURL)
+	webhookService := service.NewWebhookService(db)
+	idempotencyService := service.NewIdempotencyService(db)
+
+	// 启动回收站清理任务
+	go todoService.RunTrashPurger(context.Background())
+
+	// 启动摘要邮件发送任务
+	go digestService.RunDigestSender(context.Background())
+
+	// 启动 Webhook 投递任务
+	go webhookService.RunWebhookDispatcher(context.Background())
+
+	// 启动幂等键清理任务
+	go idempotencyService.RunIdempotencyPurger(context.Background())
 
 	// 初始化Handler层
 	userHandler := handler.NewUserHandler(userService)
 	todoHandler := handler.NewTodoHandler(todoService)
+	projectHandler := handler.NewProjectHandler(projectService, todoService)
+	calendarHandler := handler.NewCalendarHandler(calendarService)
+	caldavHandler := handler.NewCalDAVHandler(userService, todoService)
+	digestHandler := handler.NewDigestHandler(digestService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
 
 	// 初始化JWT中间件
 	jwtMiddleware, err := middleware.NewJWTMiddleware(db)
@@ -58,8 +174,17 @@ func main() {
 		})
 	})
 
+	// 缓存命中统计
+	h.GET("/metrics/cache", func(ctx context.Context, c *app.RequestContext) {
+		c.JSON(consts.StatusOK, utils.H{
+			"status": 200,
+			"msg":    "获取成功",
+			"data":   store.Metrics(),
+		})
+	})
+
 	// 设置路由
-	router.SetupRoutes(h, userHandler, todoHandler, jwtMiddleware)
+	router.SetupRoutes(h, userHandler, todoHandler, projectHandler, calendarHandler, caldavHandler, digestHandler, webhookHandler, jwtMiddleware, middleware.Idempotency(idempotencyService))
 
 	// 启动服务器
 	log.Println("Server is starting on :8080...")
@@ -0,0 +1,169 @@
+// Package cache 提供按用户划分的读缓存，支持 Redis 和进程内 LRU 两种后端。
+// 每个用户有一个版本号，缓存键包含版本号；用户的数据发生任何修改时递增版本号，
+// 旧版本的缓存不再被读取，随过期时间自然淘汰
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// opTimeout 单次缓存操作的超时时间，超时按未命中处理
+const opTimeout = 200 * time.Millisecond
+
+// Backend 缓存后端
+type Backend interface {
+	// Get 读取缓存，不存在时 ok 为 false
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set 写入缓存
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Version 读取版本号，不存在时以当前时间（纳秒）初始化，
+	// 版本号被淘汰后重新初始化的值也不会与旧版本重复
+	Version(ctx context.Context, key string) (int64, error)
+	// Bump 递增版本号，不存在时以当前时间初始化
+	Bump(ctx context.Context, key string) error
+}
+
+// Metrics 单个命名空间的缓存统计
+type Metrics struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	Errors  int64   `json:"errors"` // 后端出错的次数，出错时直接读取数据库
+	HitRate float64 `json:"hit_rate"`
+}
+
+// counters 命名空间的计数器
+type counters struct {
+	hits, misses, errors atomic.Int64
+}
+
+// Store 按用户划分的缓存，为 nil 时不缓存
+type Store struct {
+	backend Backend
+	prefix  string
+
+	mu      sync.Mutex
+	metrics map[string]*counters
+}
+
+// New 创建缓存，prefix 为所有键的前缀
+func New(backend Backend, prefix string) *Store {
+	return &Store{
+		backend: backend,
+		prefix:  prefix,
+		metrics: make(map[string]*counters),
+	}
+}
+
+// Fetch 读取用户在命名空间 namespace 下 key 对应的缓存，未命中时调用 load 并写入缓存。
+// 缓存后端出错时不影响结果，load 返回错误时不写入缓存
+func Fetch[T any](s *Store, userID int64, namespace, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	if s == nil {
+		return load()
+	}
+	c := s.counters(namespace)
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+
+	version, err := s.backend.Version(ctx, s.versionKey(userID))
+	if err != nil {
+		c.errors.Add(1)
+		log.Printf("Cache version lookup failed for user %d: %v", userID, err)
+		return load()
+	}
+	fullKey := s.prefix + "u:" + strconv.FormatInt(userID, 10) + ":v" + strconv.FormatInt(version, 10) +
+		":" + namespace + ":" + key
+
+	if data, ok, err := s.backend.Get(ctx, fullKey); err != nil {
+		c.errors.Add(1)
+		log.Printf("Cache get %s failed: %v", fullKey, err)
+	} else if ok {
+		var value T
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err == nil {
+			c.hits.Add(1)
+			return value, nil
+		}
+		c.errors.Add(1)
+	}
+	c.misses.Add(1)
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		c.errors.Add(1)
+		log.Printf("Cache encode %s failed: %v", fullKey, err)
+		return value, nil
+	}
+	// 查询数据库可能已超过读取时的超时时间，写入使用新的超时
+	setCtx, setCancel := context.WithTimeout(context.Background(), opTimeout)
+	defer setCancel()
+	if err := s.backend.Set(setCtx, fullKey, buf.Bytes(), ttl); err != nil {
+		c.errors.Add(1)
+		log.Printf("Cache set %s failed: %v", fullKey, err)
+	}
+	return value, nil
+}
+
+// Invalidate 使用户的全部缓存失效，需要在修改提交之后调用
+func (s *Store) Invalidate(userID int64) {
+	if s == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	if err := s.backend.Bump(ctx, s.versionKey(userID)); err != nil {
+		log.Printf("Cache invalidation failed for user %d: %v", userID, err)
+	}
+}
+
+// Metrics 各命名空间的命中统计
+func (s *Store) Metrics() map[string]Metrics {
+	result := make(map[string]Metrics)
+	if s == nil {
+		return result
+	}
+	s.mu.Lock()
+	names := make([]string, 0, len(s.metrics))
+	for name := range s.metrics {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		c := s.counters(name)
+		m := Metrics{Hits: c.hits.Load(), Misses: c.misses.Load(), Errors: c.errors.Load()}
+		if m.Hits+m.Misses > 0 {
+			m.HitRate = float64(m.Hits) / float64(m.Hits+m.Misses)
+		}
+		result[name] = m
+	}
+	return result
+}
+
+// counters 获取命名空间的计数器
+func (s *Store) counters(namespace string) *counters {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.metrics[namespace]
+	if !ok {
+		c = &counters{}
+		s.metrics[namespace] = c
+	}
+	return c
+}
+
+// versionKey 用户版本号的键
+func (s *Store) versionKey(userID int64) string {
+	return s.prefix + "u:" + strconv.FormatInt(userID, 10) + ":version"
+}
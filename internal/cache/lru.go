@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU 进程内的 LRU 缓存后端，只适用于单实例部署
+type LRU struct {
+	capacity int
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	order    *list.List // 最近使用的在前面
+	versions map[string]int64
+}
+
+// lruEntry LRU 中的缓存项
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRU 创建最多保存 capacity 项的 LRU 缓存。版本号单独保存，不会被淘汰
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		versions: make(map[string]int64),
+	}
+}
+
+// Get 读取缓存
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := elem.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true, nil
+}
+
+// Set 写入缓存，超出容量时淘汰最久未使用的项
+func (c *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := time.Now().Add(ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+// Version 读取版本号，不存在时以当前时间初始化
+func (c *LRU) Version(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.versions[key]; !ok {
+		c.versions[key] = time.Now().UnixNano()
+	}
+	return c.versions[key], nil
+}
+
+// Bump 递增版本号，不存在时以当前时间初始化
+func (c *LRU) Bump(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.versions[key]; !ok {
+		c.versions[key] = time.Now().UnixNano()
+		return nil
+	}
+	c.versions[key]++
+	return nil
+}
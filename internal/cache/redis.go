@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis 缓存后端，多个实例共享缓存和版本号
+type Redis struct {
+	client *redis.Client
+}
+
+// versionScript 读取版本号，不存在时以 ARGV[1]（当前时间）初始化。
+// 以字符串返回，Lua 的数字是双精度浮点数，无法精确表示纳秒时间戳
+var versionScript = redis.NewScript(`
+local v = redis.call('GET', KEYS[1])
+if v then return v end
+redis.call('SET', KEYS[1], ARGV[1])
+return ARGV[1]`)
+
+// bumpScript 递增版本号，不存在时以 ARGV[1]（当前时间）初始化
+var bumpScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then return redis.call('INCR', KEYS[1]) end
+redis.call('SET', KEYS[1], ARGV[1])
+return ARGV[1]`)
+
+// NewRedis 创建 Redis 缓存后端
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+// Get 读取缓存
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Set 写入缓存
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Version 读取版本号
+func (r *Redis) Version(ctx context.Context, key string) (int64, error) {
+	value, err := versionScript.Run(ctx, r.client, []string{key}, time.Now().UnixNano()).Text()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// Bump 递增版本号
+func (r *Redis) Bump(ctx context.Context, key string) error {
+	return bumpScript.Run(ctx, r.client, []string{key}, time.Now().UnixNano()).Err()
+}
@@ -54,5 +54,18 @@ func AutoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&model.User{},
 		&model.Todo{},
+		&model.Project{},
+		&model.ChecklistItem{},
+		&model.TodoDependency{},
+		&model.TodoActivity{},
+		&model.TodoRevision{},
+		&model.BatchUndo{},
+		&model.CalendarFeed{},
+		&model.CalendarObject{},
+		&model.AppPassword{},
+		&model.DigestSetting{},
+		&model.Webhook{},
+		&model.WebhookDelivery{},
+		&model.IdempotencyRecord{},
 	)
 }
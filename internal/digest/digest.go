@@ -0,0 +1,74 @@
+// Package digest 渲染待办事项摘要邮件的 HTML 和纯文本内容
+package digest
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	htmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/digest.html.tmpl"))
+	textTemplate = texttemplate.Must(texttemplate.ParseFS(templates, "templates/digest.txt.tmpl"))
+)
+
+// Item 摘要中的单个事项
+type Item struct {
+	Title    string
+	Project  string // 清单名称，可能为空
+	Deadline string // 按用户时区格式化的截止时间，可能为空
+	Priority string // 优先级名称，无优先级时为空
+}
+
+// Section 摘要中的一组事项
+type Section struct {
+	Title string
+	Items []Item
+	Total int // 事项总数，超过 Items 的部分不列出
+}
+
+// More 未列出的事项数
+func (s Section) More() int {
+	return s.Total - len(s.Items)
+}
+
+// Digest 摘要内容
+type Digest struct {
+	Username       string
+	Period         string // 如 "今日摘要"、"本周摘要"
+	Date           string // 按用户时区格式化的日期
+	Sections       []Section
+	AppURL         string
+	UnsubscribeURL string
+}
+
+// Empty 是否没有任何事项
+func (d *Digest) Empty() bool {
+	for _, section := range d.Sections {
+		if section.Total > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Subject 邮件主题
+func (d *Digest) Subject() string {
+	return "RemindGo " + d.Period + " · " + d.Date
+}
+
+// Render 渲染 HTML 和纯文本内容
+func Render(d *Digest) (html, text string, err error) {
+	var htmlBuf, textBuf bytes.Buffer
+	if err := htmlTemplate.ExecuteTemplate(&htmlBuf, "digest.html.tmpl", d); err != nil {
+		return "", "", err
+	}
+	if err := textTemplate.ExecuteTemplate(&textBuf, "digest.txt.tmpl", d); err != nil {
+		return "", "", err
+	}
+	return htmlBuf.String(), textBuf.String(), nil
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// GetTodoActivity 获取单个待办事项的动态
+func (h *TodoHandler) GetTodoActivity(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 解析查询参数
+	var params model.ActivityQueryParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+
+	// 调用service层获取动态
+	activity, err := h.todoService.GetTodoActivity(userID, todoID, &params)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   activity,
+	})
+}
+
+// GetActivityFeed 获取用户全部待办事项的动态
+func (h *TodoHandler) GetActivityFeed(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 解析查询参数
+	var params model.ActivityQueryParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+
+	// 调用service层获取动态
+	activity, err := h.todoService.GetActivityFeed(userID, &params)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
+			Status: consts.StatusInternalServerError,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   activity,
+	})
+}
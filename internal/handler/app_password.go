@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// GetAppPasswords 获取应用密码列表
+func (h *UserHandler) GetAppPasswords(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层获取列表
+	passwords, err := h.userService.GetAppPasswords(userID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
+			Status: consts.StatusInternalServerError,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	items := make([]model.AppPasswordResponse, len(passwords))
+	for i := range passwords {
+		items[i] = appPasswordToResponse(&passwords[i])
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   items,
+	})
+}
+
+// CreateAppPassword 创建应用密码
+func (h *UserHandler) CreateAppPassword(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.CreateAppPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层创建
+	appPassword, password, err := h.userService.CreateAppPassword(userID, &req)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "名称长度应为1-100个字符" || err.Error() == "应用密码数量已达上限" {
+			status = consts.StatusBadRequest
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	resp := appPasswordToResponse(appPassword)
+	resp.Password = password
+	c.JSON(consts.StatusCreated, model.BaseResponse{
+		Status: consts.StatusCreated,
+		Msg:    "创建成功，密码只会显示这一次",
+		Data:   resp,
+	})
+}
+
+// DeleteAppPassword 删除应用密码
+func (h *UserHandler) DeleteAppPassword(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	passwordID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层删除
+	if err := h.userService.DeleteAppPassword(userID, passwordID); err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "应用密码不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "删除成功",
+		Data:   nil,
+	})
+}
+
+// appPasswordToResponse 将应用密码模型转换为响应格式
+func appPasswordToResponse(appPassword *model.AppPassword) model.AppPasswordResponse {
+	resp := model.AppPasswordResponse{
+		ID:        appPassword.ID,
+		Name:      appPassword.Name,
+		CreatedAt: appPassword.CreatedAt.Unix(),
+	}
+	if appPassword.LastUsedAt != nil {
+		lastUsedAt := appPassword.LastUsedAt.Unix()
+		resp.LastUsedAt = &lastUsedAt
+	}
+	return resp
+}
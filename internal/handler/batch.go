@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// BatchAction 对指定ID或符合过滤条件的事项执行批量操作
+func (h *TodoHandler) BatchAction(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.BatchActionRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层执行批量操作
+	result, err := h.todoService.BatchAction(userID, &req)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		switch err.Error() {
+		case "需要指定 ids 或 filter", "ids 与 filter 只能指定一个", "指定的事项数量超过上限",
+			"批量操作类型无效", "需要指定截止时间", "没有需要更新的字段",
+			"截止时间格式错误，请使用ISO 8601格式", "优先级无效", "优先级参数无效", "清单不存在":
+			status = consts.StatusBadRequest
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "批量操作成功",
+		Data:   result,
+	})
+}
+
+// UndoBatch 撤销批量操作
+func (h *TodoHandler) UndoBatch(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.BatchUndoRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层撤销批量操作
+	result, err := h.todoService.UndoBatch(userID, req.Token)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		switch err.Error() {
+		case "撤销令牌无效":
+			status = consts.StatusNotFound
+		case "撤销令牌已过期":
+			status = consts.StatusGone
+		case "该操作已撤销":
+			status = consts.StatusConflict
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "撤销成功",
+		Data:   result,
+	})
+}
+
+// ExecuteBatch 在同一事务中按顺序执行多个操作
+func (h *TodoHandler) ExecuteBatch(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.BatchOperationsRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层执行，失败时所有操作都已回滚，返回已执行到的操作结果
+	result, err := h.todoService.ExecuteBatch(userID, &req)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		switch err.Error() {
+		case "待办事项不存在":
+			status = consts.StatusNotFound
+		case "存在未完成的前置事项":
+			status = consts.StatusConflict
+		case "操作列表不能为空", "操作数量超过上限", "操作类型无效", "操作数据格式错误",
+			"临时ID重复", "引用的临时ID不存在", "需要指定事项ID", "标题不能为空",
+			"标题不能超过255个字符", "内容不能超过1000个字符", "状态无效",
+			"截止时间格式错误，请使用ISO 8601格式", "优先级无效", "清单不存在":
+			status = consts.StatusBadRequest
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   result,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "批量执行成功",
+		Data:   result,
+	})
+}
@@ -0,0 +1,434 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"net/url"
+	"strings"
+
+	"RemindGo/internal/model"
+	"RemindGo/internal/service"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// CalDAV 相关的 XML 命名空间
+const (
+	nsDAV       = "DAV:"
+	nsCalDAV    = "urn:ietf:params:xml:ns:caldav"
+	nsCalServer = "http://calendarserver.org/ns/"
+)
+
+// CalDAV 资源路径
+const (
+	davRoot         = "/dav/"
+	davPrincipal    = "/dav/principal/"
+	davCalendarHome = "/dav/calendars/"
+	davCalendar     = "/dav/calendars/todos/"
+)
+
+// CalDAVMethods CalDAV 处理器支持的 HTTP 方法
+var CalDAVMethods = []string{"OPTIONS", "PROPFIND", "PROPPATCH", "REPORT", "GET", "HEAD", "PUT", "DELETE"}
+
+type CalDAVHandler struct {
+	userService *service.UserService
+	todoService *service.TodoService
+}
+
+// NewCalDAVHandler 创建 CalDAV 处理器
+func NewCalDAVHandler(userService *service.UserService, todoService *service.TodoService) *CalDAVHandler {
+	return &CalDAVHandler{
+		userService: userService,
+		todoService: todoService,
+	}
+}
+
+// davUser 通过认证的 CalDAV 用户
+type davUser struct {
+	id       int64
+	username string
+	email    string
+}
+
+// WellKnown 服务发现，重定向到 CalDAV 根路径（RFC 6764）
+func (h *CalDAVHandler) WellKnown(ctx context.Context, c *app.RequestContext) {
+	c.Redirect(consts.StatusMovedPermanently, []byte(davRoot))
+}
+
+// ServeDAV 处理 /dav/ 下的全部 CalDAV 请求，使用应用密码进行 Basic 认证
+func (h *CalDAVHandler) ServeDAV(ctx context.Context, c *app.RequestContext) {
+	method := string(c.Method())
+	if method == "OPTIONS" {
+		h.options(c)
+		return
+	}
+
+	user, ok := h.authenticate(c)
+	if !ok {
+		c.Header("WWW-Authenticate", `Basic realm="RemindGo CalDAV"`)
+		c.String(consts.StatusUnauthorized, "需要使用用户名和应用密码认证")
+		return
+	}
+
+	path := davPath(c)
+	switch method {
+	case "PROPFIND":
+		h.propfind(c, user, path)
+	case "PROPPATCH":
+		h.proppatch(c, path)
+	case "REPORT":
+		h.report(c, user, path)
+	case "GET", "HEAD":
+		h.get(c, user, path, method == "HEAD")
+	case "PUT":
+		h.put(c, user, path)
+	case "DELETE":
+		h.delete(c, user, path)
+	default:
+		c.String(consts.StatusMethodNotAllowed, "不支持的方法")
+	}
+}
+
+// options 声明 CalDAV 能力，不需要认证
+func (h *CalDAVHandler) options(c *app.RequestContext) {
+	c.Header("DAV", "1, 3, calendar-access")
+	c.Header("Allow", strings.Join(CalDAVMethods, ", "))
+	c.Status(consts.StatusOK)
+}
+
+// authenticate 解析 Basic 认证头并校验应用密码
+func (h *CalDAVHandler) authenticate(c *app.RequestContext) (*davUser, bool) {
+	auth := string(c.GetHeader("Authorization"))
+	if !strings.HasPrefix(auth, "Basic ") {
+		return nil, false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "Basic "))
+	if err != nil {
+		return nil, false
+	}
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return nil, false
+	}
+
+	user, err := h.userService.AuthenticateAppPassword(username, password)
+	if err != nil {
+		return nil, false
+	}
+	return &davUser{id: user.ID, username: user.Username, email: user.Email}, true
+}
+
+// propfind 查询资源属性（RFC 4918）
+func (h *CalDAVHandler) propfind(c *app.RequestContext, user *davUser, path string) {
+	var req davPropfind
+	if body := c.Request.Body(); len(bytes.TrimSpace(body)) > 0 {
+		if err := xml.Unmarshal(body, &req); err != nil {
+			c.String(consts.StatusBadRequest, "请求体格式错误")
+			return
+		}
+	}
+	requested := req.names()
+	depth := string(c.GetHeader("Depth"))
+
+	var ms davMultistatus
+	switch path {
+	case davRoot, davPrincipal, davCalendarHome:
+		ms.add(h.collectionProps(user, path), requested)
+		if depth != "0" && path == davCalendarHome {
+			props, err := h.calendarProps(user)
+			if err != nil {
+				c.String(consts.StatusInternalServerError, err.Error())
+				return
+			}
+			ms.add(props, requested)
+		}
+	case davCalendar:
+		props, err := h.calendarProps(user)
+		if err != nil {
+			c.String(consts.StatusInternalServerError, err.Error())
+			return
+		}
+		ms.add(props, requested)
+		if depth != "0" {
+			todos, err := h.todoService.CalendarObjects(user.id, false)
+			if err != nil {
+				c.String(consts.StatusInternalServerError, err.Error())
+				return
+			}
+			if err := h.addObjects(&ms, user, todos, requested, davHasProp(requested, nsCalDAV, "calendar-data")); err != nil {
+				c.String(consts.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+	default:
+		name, ok := davObjectName(path)
+		if !ok {
+			c.String(consts.StatusNotFound, "资源不存在")
+			return
+		}
+		todo, err := h.todoService.CalendarObject(user.id, name)
+		if err != nil {
+			davError(c, err)
+			return
+		}
+		if err := h.addObjects(&ms, user, []model.Todo{*todo}, requested, davHasProp(requested, nsCalDAV, "calendar-data")); err != nil {
+			c.String(consts.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	ms.write(c)
+}
+
+// proppatch 不允许修改属性，对每个属性返回 403
+func (h *CalDAVHandler) proppatch(c *app.RequestContext, path string) {
+	var req davPropertyUpdate
+	if err := xml.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.String(consts.StatusBadRequest, "请求体格式错误")
+		return
+	}
+	var names []xml.Name
+	for _, group := range append(req.Set, req.Remove...) {
+		for _, prop := range group.Prop.Props {
+			names = append(names, prop.XMLName)
+		}
+	}
+
+	var ms davMultistatus
+	ms.responses = append(ms.responses, davResponse{href: path, forbidden: names})
+	ms.write(c)
+}
+
+// report 处理 calendar-query 和 calendar-multiget（RFC 4791）
+func (h *CalDAVHandler) report(c *app.RequestContext, user *davUser, path string) {
+	var req davReport
+	if err := xml.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.String(consts.StatusBadRequest, "请求体格式错误")
+		return
+	}
+	requested := req.Prop.names()
+	withData := len(requested) == 0 || davHasProp(requested, nsCalDAV, "calendar-data")
+
+	var todos []model.Todo
+	switch {
+	case req.XMLName.Space == nsCalDAV && req.XMLName.Local == "calendar-query":
+		if path != davCalendar {
+			c.String(consts.StatusNotFound, "资源不存在")
+			return
+		}
+		matchTodo, pendingOnly := req.Filter.match()
+		if matchTodo {
+			var err error
+			if todos, err = h.todoService.CalendarObjects(user.id, pendingOnly); err != nil {
+				c.String(consts.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+	case req.XMLName.Space == nsCalDAV && req.XMLName.Local == "calendar-multiget":
+		var ms davMultistatus
+		for _, href := range req.Hrefs {
+			hrefPath, _ := url.PathUnescape(href)
+			if u, err := url.Parse(hrefPath); err == nil && u.Host != "" {
+				hrefPath = u.Path
+			}
+			name, ok := davObjectName(hrefPath)
+			if !ok {
+				ms.responses = append(ms.responses, davResponse{href: href, status: consts.StatusNotFound})
+				continue
+			}
+			todo, err := h.todoService.CalendarObject(user.id, name)
+			if err != nil {
+				ms.responses = append(ms.responses, davResponse{href: href, status: consts.StatusNotFound})
+				continue
+			}
+			if err := h.addObjects(&ms, user, []model.Todo{*todo}, requested, withData); err != nil {
+				c.String(consts.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+		ms.write(c)
+		return
+	default:
+		c.String(consts.StatusForbidden, "不支持的报告类型")
+		return
+	}
+
+	var ms davMultistatus
+	if err := h.addObjects(&ms, user, todos, requested, withData); err != nil {
+		c.String(consts.StatusInternalServerError, err.Error())
+		return
+	}
+	ms.write(c)
+}
+
+// get 获取单个事项的 iCalendar 数据
+func (h *CalDAVHandler) get(c *app.RequestContext, user *davUser, path string, head bool) {
+	name, ok := davObjectName(path)
+	if !ok {
+		c.String(consts.StatusMethodNotAllowed, "集合不支持 GET")
+		return
+	}
+	todo, err := h.todoService.CalendarObject(user.id, name)
+	if err != nil {
+		davError(c, err)
+		return
+	}
+	data, err := h.todoService.CalendarObjectData(user.id, []model.Todo{*todo})
+	if err != nil {
+		c.String(consts.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("ETag", service.TodoETag(todo))
+	if head {
+		c.SetContentType("text/calendar; charset=utf-8")
+		c.Status(consts.StatusOK)
+		return
+	}
+	c.Data(consts.StatusOK, "text/calendar; charset=utf-8", data[0])
+}
+
+// put 创建或更新事项
+func (h *CalDAVHandler) put(c *app.RequestContext, user *davUser, path string) {
+	name, ok := davObjectName(path)
+	if !ok {
+		c.String(consts.StatusMethodNotAllowed, "集合不支持 PUT")
+		return
+	}
+
+	todo, created, err := h.todoService.PutCalendarObject(user.id, name, c.Request.Body(),
+		string(c.GetHeader("If-Match")), string(c.GetHeader("If-None-Match")))
+	if err != nil {
+		davError(c, err)
+		return
+	}
+
+	// 提交的数据原样保存，之后 GET 返回相同的内容，因此可以返回 ETag
+	c.Header("ETag", service.TodoETag(todo))
+	if created {
+		c.Status(consts.StatusCreated)
+		return
+	}
+	c.Status(consts.StatusNoContent)
+}
+
+// delete 删除事项
+func (h *CalDAVHandler) delete(c *app.RequestContext, user *davUser, path string) {
+	name, ok := davObjectName(path)
+	if !ok {
+		c.String(consts.StatusForbidden, "不能删除集合")
+		return
+	}
+	if err := h.todoService.DeleteCalendarObject(user.id, name, string(c.GetHeader("If-Match"))); err != nil {
+		davError(c, err)
+		return
+	}
+	c.Status(consts.StatusNoContent)
+}
+
+// collectionProps 根路径、主体和日历主目录的属性
+func (h *CalDAVHandler) collectionProps(user *davUser, path string) davResponse {
+	resp := davResponse{href: path}
+	resp.prop(nsDAV, "current-user-principal", davHref(davPrincipal))
+	resp.prop(nsDAV, "principal-URL", davHref(davPrincipal))
+	resp.prop(nsCalDAV, "calendar-home-set", davHref(davCalendarHome))
+
+	switch path {
+	case davPrincipal:
+		resp.prop(nsDAV, "resourcetype", "<d:collection/><d:principal/>")
+		resp.prop(nsDAV, "displayname", davEscape(user.username))
+		resp.prop(nsCalDAV, "calendar-user-address-set", davHref("mailto:"+user.email))
+	default:
+		resp.prop(nsDAV, "resourcetype", "<d:collection/>")
+		resp.prop(nsDAV, "displayname", "RemindGo")
+	}
+	return resp
+}
+
+// calendarProps 待办事项日历的属性
+func (h *CalDAVHandler) calendarProps(user *davUser) (davResponse, error) {
+	ctag, err := h.todoService.CalendarCTag(user.id)
+	if err != nil {
+		return davResponse{}, err
+	}
+
+	resp := davResponse{href: davCalendar}
+	resp.prop(nsDAV, "resourcetype", "<d:collection/><c:calendar/>")
+	resp.prop(nsDAV, "displayname", "RemindGo")
+	resp.prop(nsDAV, "current-user-principal", davHref(davPrincipal))
+	resp.prop(nsDAV, "owner", davHref(davPrincipal))
+	resp.prop(nsDAV, "current-user-privilege-set",
+		"<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>"+
+			"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege>"+
+			"<d:privilege><d:unbind/></d:privilege>")
+	resp.prop(nsCalDAV, "supported-calendar-component-set", `<c:comp name="VTODO"/>`)
+	resp.prop(nsCalDAV, "calendar-description", "RemindGo 待办事项")
+	resp.prop(nsCalServer, "getctag", ctag)
+	return resp, nil
+}
+
+// addObjects 将事项作为日历对象加入多状态响应
+func (h *CalDAVHandler) addObjects(ms *davMultistatus, user *davUser, todos []model.Todo, requested []xml.Name, withData bool) error {
+	var data [][]byte
+	if withData && len(todos) > 0 {
+		var err error
+		if data, err = h.todoService.CalendarObjectData(user.id, todos); err != nil {
+			return err
+		}
+	}
+
+	for i := range todos {
+		resp := davResponse{href: davCalendar + url.PathEscape(service.CalendarObjectName(&todos[i]))}
+		resp.prop(nsDAV, "getetag", davEscape(service.TodoETag(&todos[i])))
+		resp.prop(nsDAV, "getcontenttype", "text/calendar; charset=utf-8; component=vtodo")
+		resp.prop(nsDAV, "resourcetype", "")
+		if withData {
+			resp.prop(nsCalDAV, "calendar-data", davEscape(string(data[i])))
+		}
+		ms.add(resp, requested)
+	}
+	return nil
+}
+
+// davError 将服务层错误转换为 HTTP 状态码
+func davError(c *app.RequestContext, err error) {
+	status := consts.StatusInternalServerError
+	switch {
+	case err.Error() == "待办事项不存在":
+		status = consts.StatusNotFound
+	case err.Error() == "资源已存在" || err.Error() == "资源已被修改" || err.Error() == "待办事项已被修改":
+		status = consts.StatusPreconditionFailed
+	case err.Error() == "仅支持包含一个 VTODO 的日历数据":
+		status = consts.StatusForbidden
+	case err.Error() == "日历数据格式错误" || strings.HasPrefix(err.Error(), "无法保存"):
+		status = consts.StatusBadRequest
+	}
+	c.String(status, err.Error())
+}
+
+// davPath 获取规范化的请求路径，集合以 / 结尾
+func davPath(c *app.RequestContext) string {
+	path := davRoot + strings.TrimPrefix(c.Param("path"), "/")
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	switch strings.TrimSuffix(path, "/") + "/" {
+	case davRoot, davPrincipal, davCalendarHome, davCalendar:
+		return strings.TrimSuffix(path, "/") + "/"
+	}
+	return path
+}
+
+// davObjectName 从日历对象路径中取出资源名
+func davObjectName(path string) (string, bool) {
+	if !strings.HasPrefix(path, davCalendar) {
+		return "", false
+	}
+	name := strings.TrimPrefix(path, davCalendar)
+	if name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// davNamespacePrefixes 输出时使用的命名空间前缀
+var davNamespacePrefixes = map[string]string{
+	nsDAV:       "d",
+	nsCalDAV:    "c",
+	nsCalServer: "cs",
+}
+
+// davAny 任意 XML 元素，只关心名称
+type davAny struct {
+	XMLName xml.Name
+}
+
+// davPropList <d:prop> 中请求的属性
+type davPropList struct {
+	Props []davAny `xml:",any"`
+}
+
+// names 返回属性名称，nil 表示请求全部属性
+func (l *davPropList) names() []xml.Name {
+	if l == nil {
+		return nil
+	}
+	names := make([]xml.Name, len(l.Props))
+	for i, prop := range l.Props {
+		names[i] = prop.XMLName
+	}
+	return names
+}
+
+// davPropfind PROPFIND 请求体，allprop 或空请求体时返回全部属性
+type davPropfind struct {
+	XMLName xml.Name     `xml:"DAV: propfind"`
+	Prop    *davPropList `xml:"DAV: prop"`
+}
+
+func (p *davPropfind) names() []xml.Name {
+	return p.Prop.names()
+}
+
+// davPropGroup PROPPATCH 中的 set 或 remove
+type davPropGroup struct {
+	Prop davPropList `xml:"DAV: prop"`
+}
+
+// davPropertyUpdate PROPPATCH 请求体
+type davPropertyUpdate struct {
+	XMLName xml.Name       `xml:"DAV: propertyupdate"`
+	Set     []davPropGroup `xml:"DAV: set"`
+	Remove  []davPropGroup `xml:"DAV: remove"`
+}
+
+// davReport REPORT 请求体，根元素决定报告类型
+type davReport struct {
+	XMLName xml.Name
+	Prop    *davPropList `xml:"DAV: prop"`
+	Hrefs   []string     `xml:"DAV: href"`
+	Filter  *davFilter   `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// davFilter calendar-query 的过滤条件
+type davFilter struct {
+	CompFilter davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type davCompFilter struct {
+	Name        string          `xml:"name,attr"`
+	CompFilters []davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	PropFilters []davPropFilter `xml:"urn:ietf:params:xml:ns:caldav prop-filter"`
+}
+
+type davPropFilter struct {
+	Name         string    `xml:"name,attr"`
+	IsNotDefined *struct{} `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+}
+
+// match 判断过滤条件是否匹配 VTODO，以及是否只需要未完成的事项。
+// 只支持组件过滤和 COMPLETED 未定义（未完成）过滤，其他条件返回更多结果由客户端自行过滤
+func (f *davFilter) match() (matchTodo, pendingOnly bool) {
+	if f == nil {
+		return true, false
+	}
+	if f.CompFilter.Name != "VCALENDAR" {
+		return false, false
+	}
+	if len(f.CompFilter.CompFilters) == 0 {
+		return true, false
+	}
+	for _, comp := range f.CompFilter.CompFilters {
+		if comp.Name != "VTODO" {
+			continue
+		}
+		for _, prop := range comp.PropFilters {
+			if prop.Name == "COMPLETED" && prop.IsNotDefined != nil {
+				pendingOnly = true
+			}
+		}
+		return true, pendingOnly
+	}
+	return false, false
+}
+
+// davProp 属性及其已编码的 XML 内容
+type davProp struct {
+	name  xml.Name
+	value string
+}
+
+// davResponse 多状态响应中的单个资源
+type davResponse struct {
+	href      string
+	status    int // 非 0 时表示整个资源的状态，例如 404
+	props     []davProp
+	missing   []xml.Name
+	forbidden []xml.Name
+}
+
+// prop 添加属性，value 为已编码的 XML 内容
+func (r *davResponse) prop(space, local, value string) {
+	r.props = append(r.props, davProp{name: xml.Name{Space: space, Local: local}, value: value})
+}
+
+// davMultistatus 207 多状态响应
+type davMultistatus struct {
+	responses []davResponse
+}
+
+// add 按请求的属性筛选后加入响应，requested 为空表示返回全部属性
+func (m *davMultistatus) add(resp davResponse, requested []xml.Name) {
+	if len(requested) > 0 {
+		found := make(map[xml.Name]davProp, len(resp.props))
+		for _, prop := range resp.props {
+			found[prop.name] = prop
+		}
+		resp.props = nil
+		for _, name := range requested {
+			if prop, ok := found[name]; ok {
+				resp.props = append(resp.props, prop)
+			} else {
+				resp.missing = append(resp.missing, name)
+			}
+		}
+	}
+	m.responses = append(m.responses, resp)
+}
+
+// write 输出多状态响应
+func (m *davMultistatus) write(c *app.RequestContext) {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, resp := range m.responses {
+		b.WriteString("<d:response>")
+		b.WriteString(davHref(resp.href))
+		if resp.status != 0 {
+			writeDAVStatus(&b, resp.status)
+		} else {
+			writePropstat(&b, resp.props, nil, consts.StatusOK)
+			writePropstat(&b, nil, resp.missing, consts.StatusNotFound)
+			writePropstat(&b, nil, resp.forbidden, consts.StatusForbidden)
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>")
+
+	c.Data(consts.StatusMultiStatus, "application/xml; charset=utf-8", b.Bytes())
+}
+
+// writePropstat 输出一组相同状态的属性，没有属性时不输出
+func writePropstat(b *bytes.Buffer, props []davProp, names []xml.Name, status int) {
+	if len(props) == 0 && len(names) == 0 {
+		return
+	}
+	b.WriteString("<d:propstat><d:prop>")
+	for _, prop := range props {
+		writeDAVElement(b, prop.name, prop.value)
+	}
+	for _, name := range names {
+		writeDAVElement(b, name, "")
+	}
+	b.WriteString("</d:prop>")
+	writeDAVStatus(b, status)
+	b.WriteString("</d:propstat>")
+}
+
+// writeDAVElement 输出元素，未知命名空间使用局部声明
+func writeDAVElement(b *bytes.Buffer, name xml.Name, value string) {
+	tag := name.Local
+	open := tag
+	if prefix, ok := davNamespacePrefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+		open = tag
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		open = tag + ` xmlns:x="` + davEscape(name.Space) + `"`
+	}
+	if value == "" {
+		b.WriteString("<" + open + "/>")
+		return
+	}
+	b.WriteString("<" + open + ">" + value + "</" + tag + ">")
+}
+
+// writeDAVStatus 输出状态行
+func writeDAVStatus(b *bytes.Buffer, status int) {
+	b.WriteString("<d:status>HTTP/1.1 " + strconv.Itoa(status) + " " + consts.StatusMessage(status) + "</d:status>")
+}
+
+// davHref 生成 <d:href> 元素
+func davHref(href string) string {
+	return "<d:href>" + davEscape(href) + "</d:href>"
+}
+
+// davEscape 转义 XML 文本
+func davEscape(text string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// davHasProp 判断是否明确请求了指定属性
+func davHasProp(requested []xml.Name, space, local string) bool {
+	for _, name := range requested {
+		if name.Space == space && name.Local == local {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+	"RemindGo/internal/service"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+type CalendarHandler struct {
+	calendarService *service.CalendarService
+}
+
+// NewCalendarHandler 创建日历处理器
+func NewCalendarHandler(calendarService *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// GetFeed 获取日历订阅地址
+func (h *CalendarHandler) GetFeed(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层获取订阅
+	feed, err := h.calendarService.GetFeed(userID)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "尚未创建日历订阅" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   calendarFeedToResponse(c, feed),
+	})
+}
+
+// ResetFeed 创建或重置日历订阅地址
+func (h *CalendarHandler) ResetFeed(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层生成新的订阅令牌
+	feed, err := h.calendarService.ResetFeed(userID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
+			Status: consts.StatusInternalServerError,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "订阅地址已生成",
+		Data:   calendarFeedToResponse(c, feed),
+	})
+}
+
+// RevokeFeed 撤销日历订阅
+func (h *CalendarHandler) RevokeFeed(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层撤销订阅
+	if err := h.calendarService.RevokeFeed(userID); err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "尚未创建日历订阅" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "撤销成功",
+		Data:   nil,
+	})
+}
+
+// ServeFeed 输出 iCalendar 格式的订阅内容，通过地址中的令牌鉴权，不需要JWT
+func (h *CalendarHandler) ServeFeed(ctx context.Context, c *app.RequestContext) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	var params model.CalendarFeedParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+
+	var buf bytes.Buffer
+	if err := h.calendarService.WriteFeed(token, &params, &buf); err != nil {
+		status := consts.StatusInternalServerError
+		switch err.Error() {
+		case "订阅地址无效":
+			status = consts.StatusNotFound
+		case "日历类型无效":
+			status = consts.StatusBadRequest
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="remindgo.ics"`)
+	c.Data(consts.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+}
+
+// calendarFeedToResponse 生成订阅地址，协议和主机取自当前请求
+func calendarFeedToResponse(c *app.RequestContext, feed *model.CalendarFeed) model.CalendarFeedResponse {
+	scheme := string(c.Request.Header.Peek("X-Forwarded-Proto"))
+	if scheme == "" {
+		scheme = string(c.URI().Scheme())
+	}
+	return model.CalendarFeedResponse{
+		URL:       scheme + "://" + string(c.Host()) + "/api/v1/calendar/feeds/" + feed.Token + ".ics",
+		CreatedAt: feed.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// GetChecklist 获取检查项列表
+func (h *TodoHandler) GetChecklist(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层获取
+	items, err := h.todoService.GetChecklist(userID, todoID)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   checklistToResponse(items),
+	})
+}
+
+// AddChecklistItem 添加检查项
+func (h *TodoHandler) AddChecklistItem(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.CreateChecklistItemRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层添加
+	item, err := h.todoService.AddChecklistItem(userID, todoID, &req)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusCreated, model.BaseResponse{
+		Status: consts.StatusCreated,
+		Msg:    "创建成功",
+		Data:   checklistItemToResponse(item),
+	})
+}
+
+// UpdateChecklistItem 更新检查项
+func (h *TodoHandler) UpdateChecklistItem(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, itemID, ok := parseChecklistItemPath(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateChecklistItemRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层更新
+	item, err := h.todoService.UpdateChecklistItem(userID, todoID, itemID, &req)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" || err.Error() == "检查项不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "更新成功",
+		Data:   checklistItemToResponse(item),
+	})
+}
+
+// ToggleChecklistItem 切换检查项完成状态
+func (h *TodoHandler) ToggleChecklistItem(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, itemID, ok := parseChecklistItemPath(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层切换状态
+	item, todo, err := h.todoService.ToggleChecklistItem(userID, todoID, itemID)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" || err.Error() == "检查项不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "切换成功",
+		Data: model.ChecklistToggleResponse{
+			Item: checklistItemToResponse(item),
+			Todo: todoToResponse(todo),
+		},
+	})
+}
+
+// ReorderChecklist 调整检查项顺序
+func (h *TodoHandler) ReorderChecklist(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.ReorderChecklistRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层排序
+	items, err := h.todoService.ReorderChecklist(userID, todoID, req.ItemIDs)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" {
+			status = consts.StatusNotFound
+		} else if err.Error() == "检查项列表不完整" {
+			status = consts.StatusBadRequest
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "排序成功",
+		Data:   checklistToResponse(items),
+	})
+}
+
+// DeleteChecklistItem 删除检查项
+func (h *TodoHandler) DeleteChecklistItem(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, itemID, ok := parseChecklistItemPath(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层删除
+	if err := h.todoService.DeleteChecklistItem(userID, todoID, itemID); err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" || err.Error() == "检查项不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "删除成功",
+		Data:   nil,
+	})
+}
+
+// parseChecklistItemPath 解析路径中的待办事项ID和检查项ID，失败时直接写入错误响应
+func parseChecklistItemPath(c *app.RequestContext) (int64, int64, bool) {
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return 0, 0, false
+	}
+
+	itemID, err := strconv.ParseInt(c.Param("item_id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的检查项ID",
+			Data:   nil,
+		})
+		return 0, 0, false
+	}
+	return todoID, itemID, true
+}
+
+// checklistToResponse 将检查项列表转换为响应格式
+func checklistToResponse(items []model.ChecklistItem) []model.ChecklistItemResponse {
+	resp := make([]model.ChecklistItemResponse, len(items))
+	for i := range items {
+		resp[i] = checklistItemToResponse(&items[i])
+	}
+	return resp
+}
+
+// checklistItemToResponse 将检查项模型转换为响应格式
+func checklistItemToResponse(item *model.ChecklistItem) model.ChecklistItemResponse {
+	return model.ChecklistItemResponse{
+		ID:        item.ID,
+		Title:     item.Title,
+		Done:      item.Done,
+		SortOrder: item.SortOrder,
+		CreatedAt: item.CreatedAt.Unix(),
+		UpdatedAt: item.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// AddDependency 添加前置事项
+func (h *TodoHandler) AddDependency(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.AddDependencyRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层添加
+	todo, err := h.todoService.AddDependency(userID, todoID, req.BlockedByID)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		switch err.Error() {
+		case "待办事项不存在":
+			status = consts.StatusNotFound
+		case "前置事项不存在", "不能依赖自身":
+			status = consts.StatusBadRequest
+		case "依赖关系已存在", "依赖关系存在循环":
+			status = consts.StatusConflict
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "添加成功",
+		Data:   todoToResponse(todo),
+	})
+}
+
+// RemoveDependency 移除前置事项
+func (h *TodoHandler) RemoveDependency(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	blockedByID, err := strconv.ParseInt(c.Param("blocked_by_id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的前置事项ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层移除
+	todo, err := h.todoService.RemoveDependency(userID, todoID, blockedByID)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" || err.Error() == "依赖关系不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "移除成功",
+		Data:   todoToResponse(todo),
+	})
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"html"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+	"RemindGo/internal/service"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+type DigestHandler struct {
+	digestService *service.DigestService
+}
+
+// NewDigestHandler 创建摘要邮件处理器
+func NewDigestHandler(digestService *service.DigestService) *DigestHandler {
+	return &DigestHandler{
+		digestService: digestService,
+	}
+}
+
+// GetSetting 获取摘要邮件设置
+func (h *DigestHandler) GetSetting(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层获取设置
+	setting, err := h.digestService.GetSetting(userID)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "用户不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   setting,
+	})
+}
+
+// UpdateSetting 更新摘要邮件设置
+func (h *DigestHandler) UpdateSetting(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.UpdateDigestSettingRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层更新设置
+	setting, err := h.digestService.UpdateSetting(userID, &req)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		switch err.Error() {
+		case "摘要频率无效", "发送时间无效", "发送日无效":
+			status = consts.StatusBadRequest
+		case "用户不存在":
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "更新成功",
+		Data:   setting,
+	})
+}
+
+// Preview 预览摘要邮件，format 为 html 或 text 时直接返回邮件内容
+func (h *DigestHandler) Preview(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var params model.DigestPreviewParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+
+	// 调用service层渲染摘要
+	preview, err := h.digestService.Preview(userID, params.Frequency)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		switch err.Error() {
+		case "摘要频率无效":
+			status = consts.StatusBadRequest
+		case "用户不存在":
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	switch params.Format {
+	case "html":
+		c.Data(consts.StatusOK, "text/html; charset=utf-8", []byte(preview.HTML))
+	case "text":
+		c.Data(consts.StatusOK, "text/plain; charset=utf-8", []byte(preview.Text))
+	default:
+		c.JSON(consts.StatusOK, model.BaseResponse{
+			Status: consts.StatusOK,
+			Msg:    "获取成功",
+			Data:   preview,
+		})
+	}
+}
+
+// UnsubscribePage 显示退订确认页面，不需要JWT。
+// 邮件客户端和安全扫描可能会预先访问链接，因此 GET 不退订，由页面中的表单提交 POST 完成
+func (h *DigestHandler) UnsubscribePage(ctx context.Context, c *app.RequestContext) {
+	if err := h.digestService.CheckUnsubscribeToken(c.Param("token")); err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "退订链接无效" {
+			status = consts.StatusNotFound
+		}
+		c.Data(status, "text/html; charset=utf-8", []byte(unsubscribeHTML("<p>"+html.EscapeString(err.Error())+"</p>")))
+		return
+	}
+
+	body := "<p>确定不再接收 RemindGo 摘要邮件吗？</p>" +
+		"<form method=\"post\"><button type=\"submit\">退订</button></form>"
+	c.Data(consts.StatusOK, "text/html; charset=utf-8", []byte(unsubscribeHTML(body)))
+}
+
+// Unsubscribe 通过退订确认页面的表单关闭摘要，不需要JWT。
+// 也用于邮件客户端的一键退订（RFC 8058）
+func (h *DigestHandler) Unsubscribe(ctx context.Context, c *app.RequestContext) {
+	status, msg := consts.StatusOK, "已退订 RemindGo 摘要邮件，可以随时在设置中重新开启。"
+	if err := h.digestService.Unsubscribe(c.Param("token")); err != nil {
+		status = consts.StatusInternalServerError
+		if err.Error() == "退订链接无效" {
+			status = consts.StatusNotFound
+		}
+		msg = err.Error()
+	}
+
+	c.Data(status, "text/html; charset=utf-8", []byte(unsubscribeHTML("<p>"+html.EscapeString(msg)+"</p>")))
+}
+
+// unsubscribeHTML 生成退订页面，body 为已转义的 HTML
+func unsubscribeHTML(body string) string {
+	return "<!DOCTYPE html><html><head><meta charset=\"utf-8\"><title>RemindGo</title></head>" +
+		"<body>" + body + "</body></html>"
+}
@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+	"RemindGo/internal/service"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+type ProjectHandler struct {
+	projectService *service.ProjectService
+	todoService    *service.TodoService
+}
+
+// NewProjectHandler 创建清单处理器
+func NewProjectHandler(projectService *service.ProjectService, todoService *service.TodoService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+		todoService:    todoService,
+	}
+}
+
+// GetProjectList 获取清单列表
+func (h *ProjectHandler) GetProjectList(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var params model.ProjectQueryParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+
+	// 调用service层获取列表
+	projects, err := h.projectService.GetProjectList(userID, &params)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
+			Status: consts.StatusInternalServerError,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   projects,
+	})
+}
+
+// CreateProject 创建清单
+func (h *ProjectHandler) CreateProject(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.CreateProjectRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层创建
+	project, err := h.projectService.CreateProject(userID, &req)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
+			Status: consts.StatusInternalServerError,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusCreated, model.BaseResponse{
+		Status: consts.StatusCreated,
+		Msg:    "创建成功",
+		Data:   projectToResponse(project),
+	})
+}
+
+// GetProject 获取单个清单
+func (h *ProjectHandler) GetProject(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	id := c.Param("id")
+	projectID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层获取
+	project, err := h.projectService.GetProjectByID(userID, projectID)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "清单不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   projectToResponse(project),
+	})
+}
+
+// UpdateProject 更新清单
+func (h *ProjectHandler) UpdateProject(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	id := c.Param("id")
+	projectID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.UpdateProjectRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层更新
+	project, err := h.projectService.UpdateProject(userID, projectID, &req)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "清单不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "更新成功",
+		Data:   projectToResponse(project),
+	})
+}
+
+// DeleteProject 删除清单
+func (h *ProjectHandler) DeleteProject(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	id := c.Param("id")
+	projectID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层删除
+	if err := h.projectService.DeleteProject(userID, projectID); err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "清单不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "删除成功",
+		Data:   nil,
+	})
+}
+
+// GetProjectTodos 获取清单下的待办事项列表
+func (h *ProjectHandler) GetProjectTodos(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	id := c.Param("id")
+	projectID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 确认清单存在且属于当前用户
+	if _, err := h.projectService.GetProjectByID(userID, projectID); err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "清单不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 解析查询参数
+	var params model.TodoQueryParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+	params.ProjectID = projectID
+
+	// 调用service层获取列表
+	listResponse, err := h.todoService.GetTodoList(userID, &params)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "优先级参数无效" {
+			status = consts.StatusBadRequest
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   listResponse,
+	})
+}
+
+// projectToResponse 将Project模型转换为响应格式
+func projectToResponse(project *model.Project) model.ProjectResponse {
+	return model.ProjectResponse{
+		ID:        project.ID,
+		Name:      project.Name,
+		Color:     project.Color,
+		Archived:  project.Archived,
+		SortOrder: project.SortOrder,
+		CreatedAt: project.CreatedAt.Unix(),
+		UpdatedAt: project.UpdatedAt.Unix(),
+	}
+}
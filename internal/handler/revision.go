@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// GetRevisions 获取版本列表
+func (h *TodoHandler) GetRevisions(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层获取版本列表
+	revisions, err := h.todoService.GetRevisions(userID, todoID)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	items := make([]model.RevisionResponse, len(revisions))
+	for i := range revisions {
+		items[i] = revisionToResponse(&revisions[i])
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   items,
+	})
+}
+
+// GetRevision 获取指定版本
+func (h *TodoHandler) GetRevision(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, rev, ok := parseRevisionPath(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层获取版本
+	revision, err := h.todoService.GetRevision(userID, todoID, rev)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" || err.Error() == "版本不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   revisionToResponse(revision),
+	})
+}
+
+// DiffRevisions 比较两个版本
+func (h *TodoHandler) DiffRevisions(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return
+	}
+
+	var params model.RevisionDiffParams
+	if err := c.Bind(&params); err != nil || params.From < 1 {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: 需要指定起始版本 from",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层比较版本
+	diff, err := h.todoService.DiffRevisions(userID, todoID, params.From, params.To)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" || err.Error() == "版本不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   diff,
+	})
+}
+
+// RestoreRevision 恢复到指定版本
+func (h *TodoHandler) RestoreRevision(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	todoID, rev, ok := parseRevisionPath(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层恢复版本
+	todo, err := h.todoService.RestoreRevision(userID, todoID, rev)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "待办事项不存在" || err.Error() == "版本不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "恢复成功",
+		Data:   todoToResponse(todo),
+	})
+}
+
+// parseRevisionPath 解析路径中的待办事项ID和版本号，失败时直接写入错误响应
+func parseRevisionPath(c *app.RequestContext) (int64, int, bool) {
+	todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return 0, 0, false
+	}
+
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的版本号",
+			Data:   nil,
+		})
+		return 0, 0, false
+	}
+	return todoID, rev, true
+}
+
+// revisionToResponse 将版本模型转换为响应格式
+func revisionToResponse(revision *model.TodoRevision) model.RevisionResponse {
+	return model.RevisionResponse{
+		Rev:       revision.Rev,
+		Title:     revision.Title,
+		Content:   revision.Content,
+		CreatedAt: revision.CreatedAt.Unix(),
+	}
+}
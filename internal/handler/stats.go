@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// GetStatsTimeseries 获取按时间段划分的统计信息
+func (h *TodoHandler) GetStatsTimeseries(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var params model.StatsTimeseriesParams
+	if err := c.Bind(&params); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层获取统计
+	stats, err := h.todoService.GetStatsTimeseries(userID, &params)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		switch err.Error() {
+		case "时区无效", "统计粒度无效", "统计范围过大", "开始日期不能晚于结束日期", "日期格式错误，请使用 YYYY-MM-DD":
+			status = consts.StatusBadRequest
+		case "用户不存在":
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   stats,
+	})
+}
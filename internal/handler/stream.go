@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+	"RemindGo/internal/realtime"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/network"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/cloudwego/hertz/pkg/protocol/http1/resp"
+)
+
+// StreamHeartbeatInterval 心跳间隔，同时按此间隔检查是否有遗漏的变更
+var StreamHeartbeatInterval = 25 * time.Second
+
+// streamPageSize 每次查询的变更数
+const streamPageSize = 100
+
+// StreamChanges 通过 Server-Sent Events 推送当前用户的变更。
+// 事件ID为变更ID，浏览器断线重连时会通过 Last-Event-ID 请求头续传
+func (h *TodoHandler) StreamChanges(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	notify, unsubscribe := h.todoService.SubscribeChanges(userID)
+	defer unsubscribe()
+	cursor, reset, err := h.todoService.ResumeCursor(userID, lastEventID(c))
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
+			Status: consts.StatusInternalServerError,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.SetStatusCode(consts.StatusOK)
+	c.Response.Header.SetContentType("text/event-stream; charset=utf-8")
+	c.Response.Header.Set("Cache-Control", "no-cache")
+	c.Response.Header.Set("X-Accel-Buffering", "no") // 关闭 Nginx 的响应缓冲
+	writer := resp.NewChunkedBodyWriter(&c.Response, c.GetWriter())
+	c.Response.HijackWriter(writer)
+
+	write := func(data string) error {
+		if _, err := writer.Write([]byte(data)); err != nil {
+			return err
+		}
+		return writer.Flush()
+	}
+	if err := write("retry: 3000\n\n"); err != nil {
+		return
+	}
+
+	send := func(event *model.ChangeEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		return write("id: " + strconv.FormatInt(event.ID, 10) + "\nevent: " + event.Type + "\ndata: " + string(data) + "\n\n")
+	}
+	heartbeat := func() error {
+		return write(": heartbeat\n\n")
+	}
+	h.pushChanges(userID, cursor, reset, notify, ctx.Done(), send, heartbeat)
+}
+
+// StreamChangesWebSocket 通过 WebSocket 推送当前用户的变更，消息为 JSON 格式的变更。
+// 续传时通过 last_event_id 查询参数指定最后收到的变更ID，心跳使用 ping 帧
+func (h *TodoHandler) StreamChangesWebSocket(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	key := string(c.GetHeader("Sec-WebSocket-Key"))
+	if string(c.Method()) != consts.MethodGet || !realtime.IsUpgradeRequest(string(c.GetHeader("Upgrade")),
+		string(c.GetHeader("Connection")), string(c.GetHeader("Sec-WebSocket-Version")), key) {
+		c.Header("Sec-WebSocket-Version", "13")
+		c.JSON(consts.StatusUpgradeRequired, model.BaseResponse{
+			Status: consts.StatusUpgradeRequired,
+			Msg:    "需要 WebSocket 握手",
+			Data:   nil,
+		})
+		return
+	}
+
+	notify, unsubscribe := h.todoService.SubscribeChanges(userID)
+	cursor, reset, err := h.todoService.ResumeCursor(userID, lastEventID(c))
+	if err != nil {
+		unsubscribe()
+		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
+			Status: consts.StatusInternalServerError,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.SetStatusCode(consts.StatusSwitchingProtocols)
+	c.Response.Header.Set("Upgrade", "websocket")
+	c.Response.Header.Set("Connection", "Upgrade")
+	c.Response.Header.Set("Sec-WebSocket-Accept", realtime.AcceptKey(key))
+
+	// 握手响应发送之后接管连接，处理函数返回后连接由框架关闭
+	c.Hijack(func(conn network.Conn) {
+		defer unsubscribe()
+		ws := realtime.NewConn(conn)
+
+		// 读取客户端的消息：回复 ping，收到关闭帧或超过两个心跳周期没有任何消息（包括 pong）时结束
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				ws.SetReadDeadline(time.Now().Add(2 * StreamHeartbeatInterval))
+				if _, _, err := ws.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		send := func(event *model.ChangeEvent) error {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			return ws.WriteText(data)
+		}
+		heartbeat := func() error {
+			return ws.WriteControl(realtime.OpPing, nil)
+		}
+		h.pushChanges(userID, cursor, reset, notify, closed, send, heartbeat)
+		ws.WriteClose(realtime.CloseGoingAway, "")
+		ws.Close()
+		<-closed
+	})
+}
+
+// pushChanges 推送变更直到 done 关闭或发送失败：先补发 cursor 之后的变更，
+// 之后在收到通知和每次心跳时推送新的变更
+func (h *TodoHandler) pushChanges(userID, cursor int64, reset bool, notify <-chan struct{}, done <-chan struct{},
+	send func(*model.ChangeEvent) error, heartbeat func() error) {
+	if reset {
+		if err := send(&model.ChangeEvent{ID: cursor, Type: model.ChangeReset}); err != nil {
+			return
+		}
+	}
+
+	flush := func() error {
+		for {
+			events, err := h.todoService.GetChanges(userID, cursor, streamPageSize)
+			if err != nil {
+				return nil // 数据库暂时不可用，下次心跳时重试
+			}
+			for i := range events {
+				if err := send(&events[i]); err != nil {
+					return err
+				}
+				cursor = events[i].ID
+			}
+			if len(events) < streamPageSize {
+				return nil
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(StreamHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-notify:
+			if err := flush(); err != nil {
+				return
+			}
+		case <-ticker.C:
+			// 通知可能因实例间转发失败而丢失，心跳时顺便检查
+			if err := heartbeat(); err != nil {
+				return
+			}
+			if err := flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// lastEventID 读取续传位置，优先使用 Last-Event-ID 请求头
+func lastEventID(c *app.RequestContext) int64 {
+	if value := string(c.GetHeader("Last-Event-ID")); value != "" {
+		if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return id
+		}
+	}
+	var params model.StreamParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，从最新的变更开始
+	}
+	return params.LastEventID
+}
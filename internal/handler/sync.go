@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// Sync 增量同步，返回令牌之后新建、修改的事项和已删除事项的墓碑，供离线客户端使用
+func (h *TodoHandler) Sync(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 解析查询参数
+	var params model.SyncParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+
+	// 调用service层同步
+	result, err := h.todoService.Sync(userID, &params)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "同步令牌无效" {
+			status = consts.StatusBadRequest
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "同步成功",
+		Data:   result,
+	})
+}
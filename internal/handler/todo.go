@@ -42,8 +42,12 @@ func (h *TodoHandler) GetTodoList(ctx context.Context, c *app.RequestContext) {
 	// 调用service层获取列表
 	listResponse, err := h.todoService.GetTodoList(userID, &params)
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
-			Status: consts.StatusInternalServerError,
+		status := consts.StatusInternalServerError
+		if err.Error() == "优先级参数无效" {
+			status = consts.StatusBadRequest
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
 			Msg:    err.Error(),
 			Data:   nil,
 		})
@@ -83,7 +87,7 @@ func (h *TodoHandler) CreateTodo(ctx context.Context, c *app.RequestContext) {
 	todo, err := h.todoService.CreateTodo(userID, &req)
 	if err != nil {
 		status := consts.StatusInternalServerError
-		if err.Error() == "截止时间格式错误，请使用ISO 8601格式" {
+		if err.Error() == "截止时间格式错误，请使用ISO 8601格式" || err.Error() == "清单不存在" || err.Error() == "优先级无效" {
 			status = consts.StatusBadRequest
 		}
 		c.JSON(status, model.BaseResponse{
@@ -94,6 +98,7 @@ func (h *TodoHandler) CreateTodo(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	c.Header("ETag", service.TodoETag(todo))
 	c.JSON(consts.StatusCreated, model.BaseResponse{
 		Status: consts.StatusCreated,
 		Msg:    "创建成功",
@@ -139,6 +144,14 @@ func (h *TodoHandler) GetTodo(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	// 条件请求，事项未修改时返回 304
+	etag := service.TodoETag(todo)
+	c.Header("ETag", etag)
+	if ifNoneMatch := string(c.GetHeader("If-None-Match")); ifNoneMatch != "" && service.MatchETag(ifNoneMatch, etag) {
+		c.SetStatusCode(consts.StatusNotModified)
+		return
+	}
+
 	c.JSON(consts.StatusOK, model.BaseResponse{
 		Status: consts.StatusOK,
 		Msg:    "获取成功",
@@ -180,12 +193,18 @@ func (h *TodoHandler) UpdateTodo(ctx context.Context, c *app.RequestContext) {
 	}
 
 	// 调用service层更新
-	todo, err := h.todoService.UpdateTodo(userID, todoID, &req)
+	// force=true 时忽略未完成的前置事项
+	force := c.Query("force") == "true"
+	todo, err := h.todoService.UpdateTodo(userID, todoID, &req, force, string(c.GetHeader("If-Match")))
 	if err != nil {
 		status := consts.StatusInternalServerError
 		if err.Error() == "待办事项不存在" {
 			status = consts.StatusNotFound
-		} else if err.Error() == "截止时间格式错误，请使用ISO 8601格式" {
+		} else if err.Error() == "待办事项已被修改" {
+			status = consts.StatusPreconditionFailed
+		} else if err.Error() == "存在未完成的前置事项" {
+			status = consts.StatusConflict
+		} else if err.Error() == "截止时间格式错误，请使用ISO 8601格式" || err.Error() == "清单不存在" || err.Error() == "优先级无效" {
 			status = consts.StatusBadRequest
 		}
 		c.JSON(status, model.BaseResponse{
@@ -196,6 +215,7 @@ func (h *TodoHandler) UpdateTodo(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	c.Header("ETag", service.TodoETag(todo))
 	c.JSON(consts.StatusOK, model.BaseResponse{
 		Status: consts.StatusOK,
 		Msg:    "更新成功",
@@ -227,10 +247,12 @@ func (h *TodoHandler) DeleteTodo(ctx context.Context, c *app.RequestContext) {
 	}
 
 	// 调用service层删除
-	if err := h.todoService.DeleteTodo(userID, todoID); err != nil {
+	if err := h.todoService.DeleteTodo(userID, todoID, string(c.GetHeader("If-Match"))); err != nil {
 		status := consts.StatusInternalServerError
 		if err.Error() == "待办事项不存在" {
 			status = consts.StatusNotFound
+		} else if err.Error() == "待办事项已被修改" {
+			status = consts.StatusPreconditionFailed
 		}
 		c.JSON(status, model.BaseResponse{
 			Status: status,
@@ -270,12 +292,19 @@ func (h *TodoHandler) ToggleTodo(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	// force=true 时忽略未完成的前置事项
+	force := c.Query("force") == "true"
+
 	// 调用service层切换状态
-	todo, err := h.todoService.ToggleTodo(userID, todoID)
+	todo, err := h.todoService.ToggleTodo(userID, todoID, force, string(c.GetHeader("If-Match")))
 	if err != nil {
 		status := consts.StatusInternalServerError
 		if err.Error() == "待办事项不存在" {
 			status = consts.StatusNotFound
+		} else if err.Error() == "存在未完成的前置事项" {
+			status = consts.StatusConflict
+		} else if err.Error() == "待办事项已被修改" {
+			status = consts.StatusPreconditionFailed
 		}
 		c.JSON(status, model.BaseResponse{
 			Status: status,
@@ -285,6 +314,7 @@ func (h *TodoHandler) ToggleTodo(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	c.Header("ETag", service.TodoETag(todo))
 	c.JSON(consts.StatusOK, model.BaseResponse{
 		Status: consts.StatusOK,
 		Msg:    "切换成功",
@@ -304,8 +334,14 @@ func (h *TodoHandler) BatchComplete(ctx context.Context, c *app.RequestContext)
 		return
 	}
 
+	// 解析批量操作范围
+	var scope model.BatchScopeParams
+	if err := c.Bind(&scope); err != nil {
+		// 忽略绑定错误，默认作用于全部事项
+	}
+
 	// 调用service层批量完成
-	count, err := h.todoService.BatchComplete(userID)
+	result, err := h.todoService.BatchComplete(userID, scope.ProjectID, scope.Force)
 	if err != nil {
 		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
 			Status: consts.StatusInternalServerError,
@@ -318,9 +354,7 @@ func (h *TodoHandler) BatchComplete(ctx context.Context, c *app.RequestContext)
 	c.JSON(consts.StatusOK, model.BaseResponse{
 		Status: consts.StatusOK,
 		Msg:    "批量完成成功",
-		Data: model.BatchOperationResult{
-			AffectedCount: count,
-		},
+		Data:   result,
 	})
 }
 
@@ -336,8 +370,14 @@ func (h *TodoHandler) BatchPending(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	// 解析批量操作范围
+	var scope model.BatchScopeParams
+	if err := c.Bind(&scope); err != nil {
+		// 忽略绑定错误，默认作用于全部事项
+	}
+
 	// 调用service层批量重置
-	count, err := h.todoService.BatchPending(userID)
+	result, err := h.todoService.BatchPending(userID, scope.ProjectID)
 	if err != nil {
 		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
 			Status: consts.StatusInternalServerError,
@@ -350,9 +390,7 @@ func (h *TodoHandler) BatchPending(ctx context.Context, c *app.RequestContext) {
 	c.JSON(consts.StatusOK, model.BaseResponse{
 		Status: consts.StatusOK,
 		Msg:    "批量重置成功",
-		Data: model.BatchOperationResult{
-			AffectedCount: count,
-		},
+		Data:   result,
 	})
 }
 
@@ -368,8 +406,14 @@ func (h *TodoHandler) BatchClearCompleted(ctx context.Context, c *app.RequestCon
 		return
 	}
 
+	// 解析批量操作范围
+	var scope model.BatchScopeParams
+	if err := c.Bind(&scope); err != nil {
+		// 忽略绑定错误，默认作用于全部事项
+	}
+
 	// 调用service层批量删除
-	count, err := h.todoService.BatchClearCompleted(userID)
+	result, err := h.todoService.BatchClearCompleted(userID, scope.ProjectID)
 	if err != nil {
 		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
 			Status: consts.StatusInternalServerError,
@@ -382,9 +426,7 @@ func (h *TodoHandler) BatchClearCompleted(ctx context.Context, c *app.RequestCon
 	c.JSON(consts.StatusOK, model.BaseResponse{
 		Status: consts.StatusOK,
 		Msg:    "批量删除成功",
-		Data: model.BatchOperationResult{
-			AffectedCount: count,
-		},
+		Data:   result,
 	})
 }
 
@@ -400,8 +442,14 @@ func (h *TodoHandler) BatchClearPending(ctx context.Context, c *app.RequestConte
 		return
 	}
 
+	// 解析批量操作范围
+	var scope model.BatchScopeParams
+	if err := c.Bind(&scope); err != nil {
+		// 忽略绑定错误，默认作用于全部事项
+	}
+
 	// 调用service层批量删除
-	count, err := h.todoService.BatchClearPending(userID)
+	result, err := h.todoService.BatchClearPending(userID, scope.ProjectID)
 	if err != nil {
 		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
 			Status: consts.StatusInternalServerError,
@@ -414,9 +462,7 @@ func (h *TodoHandler) BatchClearPending(ctx context.Context, c *app.RequestConte
 	c.JSON(consts.StatusOK, model.BaseResponse{
 		Status: consts.StatusOK,
 		Msg:    "批量删除成功",
-		Data: model.BatchOperationResult{
-			AffectedCount: count,
-		},
+		Data:   result,
 	})
 }
 
@@ -435,8 +481,12 @@ func (h *TodoHandler) GetStats(ctx context.Context, c *app.RequestContext) {
 	// 调用service层获取统计
 	stats, err := h.todoService.GetStats(userID)
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
-			Status: consts.StatusInternalServerError,
+		status := consts.StatusInternalServerError
+		if err.Error() == "用户不存在" {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
 			Msg:    err.Error(),
 			Data:   nil,
 		})
@@ -457,8 +507,21 @@ func todoToResponse(todo *model.Todo) model.TodoResponse {
 		Title:     todo.Title,
 		Content:   todo.Content,
 		Status:    todo.Status,
+		Priority:  todo.Priority,
+		ProjectID: todo.ProjectID,
 		CreatedAt: todo.CreatedAt.Unix(),
 		UpdatedAt: todo.UpdatedAt.Unix(),
+		Version:   todo.Version,
+		Progress:  service.ChecklistProgress(todo.ChecklistItems),
+		BlockedBy: service.BlockedByIDs(todo),
+		Blocking:  service.BlockingIDs(todo),
+
+		ChecklistAutoComplete: todo.ChecklistAutoComplete,
+	}
+
+	if todo.DeletedAt.Valid {
+		deletedAt := todo.DeletedAt.Time.Unix()
+		resp.DeletedAt = &deletedAt
 	}
 
 	if todo.Deadline != nil {
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// ExportTodos 导出全部待办事项
+func (h *TodoHandler) ExportTodos(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var params model.ExportQueryParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+	if params.Format == "" {
+		params.Format = model.TransferFormatJSON
+	}
+	contentType, extension := exportContentType(params.Format)
+	if contentType == "" {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "导出格式无效",
+			Data:   nil,
+		})
+		return
+	}
+
+	filename := "todos-" + time.Now().Format("20060102") + "." + extension
+	c.SetContentType(contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	// 边查询边写出，避免一次性加载全部事项
+	pr, pw := io.Pipe()
+	go func() {
+		err := h.todoService.ExportTodos(userID, params.Format, pw)
+		if err != nil {
+			log.Printf("Failed to export todos for user %d: %v", userID, err)
+		}
+		pw.CloseWithError(err)
+	}()
+	c.SetBodyStream(pr, -1)
+}
+
+// ImportTodos 导入待办事项，请求体为导出格式的文件内容，也可以通过 multipart 的 file 字段上传
+func (h *TodoHandler) ImportTodos(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var params model.ImportQueryParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+
+	data, filename, err := readUpload(c)
+	if err != nil || len(data) == 0 {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: 缺少导入文件",
+			Data:   nil,
+		})
+		return
+	}
+	if params.Format == "" {
+		params.Format = detectImportFormat(filename, string(c.ContentType()))
+	}
+
+	// 调用service层导入
+	result, err := h.todoService.ImportTodos(userID, params.Format, filename, data, params.DryRun)
+	if err != nil {
+		status := consts.StatusInternalServerError
+		if err.Error() == "导入格式无效" || err.Error() == "导入记录数超过上限" ||
+			strings.HasPrefix(err.Error(), "导入文件格式错误") {
+			status = consts.StatusBadRequest
+		}
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	if len(result.Errors) > 0 {
+		c.JSON(consts.StatusUnprocessableEntity, model.BaseResponse{
+			Status: consts.StatusUnprocessableEntity,
+			Msg:    "导入数据校验失败",
+			Data:   result,
+		})
+		return
+	}
+
+	status := consts.StatusCreated
+	msg := "导入成功"
+	if params.DryRun {
+		status = consts.StatusOK
+		msg = "校验通过"
+	}
+	c.JSON(status, model.BaseResponse{
+		Status: status,
+		Msg:    msg,
+		Data:   result,
+	})
+}
+
+// exportContentType 导出格式对应的内容类型和文件扩展名，格式无效时返回空字符串
+func exportContentType(format string) (string, string) {
+	switch format {
+	case model.TransferFormatJSON:
+		return "application/json; charset=utf-8", "json"
+	case model.TransferFormatCSV:
+		return "text/csv; charset=utf-8", "csv"
+	case model.TransferFormatMarkdown:
+		return "text/markdown; charset=utf-8", "md"
+	}
+	return "", ""
+}
+
+// detectImportFormat 根据文件名或内容类型判断导入格式，无法判断时为 JSON
+func detectImportFormat(filename, contentType string) string {
+	filename = strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(filename, ".csv") || strings.Contains(contentType, "csv"):
+		return model.TransferFormatCSV
+	case strings.HasSuffix(filename, ".ics") || strings.Contains(contentType, "text/calendar"):
+		return model.TransferFormatICS
+	case strings.HasSuffix(filename, ".md") || strings.HasSuffix(filename, ".markdown") ||
+		strings.Contains(contentType, "text/markdown"):
+		return model.TransferFormatMarkdown
+	}
+	return model.TransferFormatJSON
+}
+
+// readUpload 读取上传的文件内容，优先使用 multipart 的 file 字段，否则使用请求体
+func readUpload(c *app.RequestContext) ([]byte, string, error) {
+	if strings.HasPrefix(string(c.ContentType()), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", err
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		return data, fileHeader.Filename, err
+	}
+	return c.Request.Body(), "", nil
+}
@@ -56,6 +56,7 @@ func (h *UserHandler) Register(ctx context.Context, c *app.RequestContext) {
 			ID:        user.ID,
 			Username:  user.Username,
 			Email:     user.Email,
+			TimeZone:  user.TimeZone,
 			CreatedAt: user.CreatedAt.Unix(),
 		},
 	})
@@ -105,6 +106,7 @@ func (h *UserHandler) GetProfile(ctx context.Context, c *app.RequestContext) {
 			ID:        user.ID,
 			Username:  user.Username,
 			Email:     user.Email,
+			TimeZone:  user.TimeZone,
 			CreatedAt: user.CreatedAt.Unix(),
 		},
 	})
@@ -140,6 +142,8 @@ func (h *UserHandler) UpdateProfile(ctx context.Context, c *app.RequestContext)
 			status = consts.StatusNotFound
 		} else if err.Error() == "用户名已被占用" || err.Error() == "邮箱已被占用" {
 			status = consts.StatusConflict
+		} else if err.Error() == "时区无效" {
+			status = consts.StatusBadRequest
 		}
 		c.JSON(status, model.BaseResponse{
 			Status: status,
@@ -156,6 +160,7 @@ func (h *UserHandler) UpdateProfile(ctx context.Context, c *app.RequestContext)
 			ID:        user.ID,
 			Username:  user.Username,
 			Email:     user.Email,
+			TimeZone:  user.TimeZone,
 			CreatedAt: user.CreatedAt.Unix(),
 		},
 	})
@@ -0,0 +1,381 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"RemindGo/internal/middleware"
+	"RemindGo/internal/model"
+	"RemindGo/internal/service"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler 创建 Webhook 处理器
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// webhookErrorStatus 根据错误信息确定状态码
+func webhookErrorStatus(err error) int {
+	switch err.Error() {
+	case "Webhook 不存在", "投递记录不存在":
+		return consts.StatusNotFound
+	case "Webhook 地址无效", "Webhook 地址不能指向内网", "至少需要订阅一个事件", "描述不能超过255个字符", "Webhook 数量已达上限":
+		return consts.StatusBadRequest
+	case "Webhook 已停用", "投递尚未完成":
+		return consts.StatusConflict
+	}
+	if strings.HasPrefix(err.Error(), "不支持的事件") {
+		return consts.StatusBadRequest
+	}
+	return consts.StatusInternalServerError
+}
+
+// GetWebhooks 获取 Webhook 列表
+func (h *WebhookHandler) GetWebhooks(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层获取列表
+	hooks, err := h.webhookService.GetWebhooks(userID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, model.BaseResponse{
+			Status: consts.StatusInternalServerError,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   hooks,
+	})
+}
+
+// CreateWebhook 创建 Webhook
+func (h *WebhookHandler) CreateWebhook(ctx context.Context, c *app.RequestContext) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return
+	}
+
+	var req model.CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层创建
+	hook, err := h.webhookService.CreateWebhook(userID, &req)
+	if err != nil {
+		status := webhookErrorStatus(err)
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "创建成功，请保存签名密钥，之后不会再次显示",
+		Data:   hook,
+	})
+}
+
+// GetWebhook 获取单个 Webhook
+func (h *WebhookHandler) GetWebhook(ctx context.Context, c *app.RequestContext) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层获取
+	hook, err := h.webhookService.GetWebhook(userID, webhookID)
+	if err != nil {
+		status := webhookErrorStatus(err)
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   hook,
+	})
+}
+
+// UpdateWebhook 更新 Webhook
+func (h *WebhookHandler) UpdateWebhook(ctx context.Context, c *app.RequestContext) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "请求参数错误: " + err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	// 调用service层更新
+	hook, err := h.webhookService.UpdateWebhook(userID, webhookID, &req)
+	if err != nil {
+		status := webhookErrorStatus(err)
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "更新成功",
+		Data:   hook,
+	})
+}
+
+// DeleteWebhook 删除 Webhook
+func (h *WebhookHandler) DeleteWebhook(ctx context.Context, c *app.RequestContext) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层删除
+	if err := h.webhookService.DeleteWebhook(userID, webhookID); err != nil {
+		status := webhookErrorStatus(err)
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "删除成功",
+		Data:   nil,
+	})
+}
+
+// RotateSecret 重置签名密钥
+func (h *WebhookHandler) RotateSecret(ctx context.Context, c *app.RequestContext) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层重置密钥
+	hook, err := h.webhookService.RotateSecret(userID, webhookID)
+	if err != nil {
+		status := webhookErrorStatus(err)
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "密钥已重置，旧密钥立即失效",
+		Data:   hook,
+	})
+}
+
+// Ping 发送测试事件
+func (h *WebhookHandler) Ping(ctx context.Context, c *app.RequestContext) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层发送测试事件
+	delivery, err := h.webhookService.Ping(userID, webhookID)
+	if err != nil {
+		status := webhookErrorStatus(err)
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusAccepted, model.BaseResponse{
+		Status: consts.StatusAccepted,
+		Msg:    "测试事件已加入发送队列",
+		Data:   delivery,
+	})
+}
+
+// GetDeliveries 获取投递记录
+func (h *WebhookHandler) GetDeliveries(ctx context.Context, c *app.RequestContext) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	// 解析查询参数
+	var params model.WebhookDeliveryQueryParams
+	if err := c.Bind(&params); err != nil {
+		// 忽略绑定错误，使用默认值
+	}
+
+	// 调用service层获取投递记录
+	deliveries, err := h.webhookService.GetDeliveries(userID, webhookID, &params)
+	if err != nil {
+		status := webhookErrorStatus(err)
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   deliveries,
+	})
+}
+
+// GetDelivery 获取单条投递记录，包含请求体
+func (h *WebhookHandler) GetDelivery(ctx context.Context, c *app.RequestContext) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	deliveryID, ok := deliveryParam(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层获取投递记录
+	delivery, err := h.webhookService.GetDelivery(userID, webhookID, deliveryID)
+	if err != nil {
+		status := webhookErrorStatus(err)
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, model.BaseResponse{
+		Status: consts.StatusOK,
+		Msg:    "获取成功",
+		Data:   delivery,
+	})
+}
+
+// Redeliver 重新投递
+func (h *WebhookHandler) Redeliver(ctx context.Context, c *app.RequestContext) {
+	userID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	deliveryID, ok := deliveryParam(c)
+	if !ok {
+		return
+	}
+
+	// 调用service层重新投递
+	delivery, err := h.webhookService.Redeliver(userID, webhookID, deliveryID)
+	if err != nil {
+		status := webhookErrorStatus(err)
+		c.JSON(status, model.BaseResponse{
+			Status: status,
+			Msg:    err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	c.JSON(consts.StatusAccepted, model.BaseResponse{
+		Status: consts.StatusAccepted,
+		Msg:    "已加入发送队列",
+		Data:   delivery,
+	})
+}
+
+// webhookParams 读取当前用户和地址中的 Webhook ID，失败时已写入响应
+func webhookParams(c *app.RequestContext) (int64, int64, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(consts.StatusUnauthorized, model.BaseResponse{
+			Status: consts.StatusUnauthorized,
+			Msg:    "未认证",
+			Data:   nil,
+		})
+		return 0, 0, false
+	}
+
+	webhookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return 0, 0, false
+	}
+	return userID, webhookID, true
+}
+
+// deliveryParam 读取地址中的投递记录ID，失败时已写入响应
+func deliveryParam(c *app.RequestContext) (int64, bool) {
+	deliveryID, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, model.BaseResponse{
+			Status: consts.StatusBadRequest,
+			Msg:    "无效的ID",
+			Data:   nil,
+		})
+		return 0, false
+	}
+	return deliveryID, true
+}
@@ -0,0 +1,249 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Decode 解析 iCalendar 文本，返回最外层组件（通常是 VCALENDAR）
+func Decode(r io.Reader) (*Component, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *Component
+	var stack []*Component
+	for _, l := range lines {
+		prop, err := parseContentLine(l.text)
+		if err != nil {
+			return nil, fmt.Errorf("第%d行: %v", l.number, err)
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			c := &Component{Name: strings.ToUpper(prop.Value), Line: l.number}
+			if len(stack) > 0 {
+				stack[len(stack)-1].AddComponent(c)
+			} else if root == nil {
+				root = c
+			} else {
+				return nil, fmt.Errorf("第%d行: 存在多个顶层组件", l.number)
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("第%d行: END:%s 与 BEGIN 不匹配", l.number, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("第%d行: 属性不在组件内", l.number)
+			}
+			c := stack[len(stack)-1]
+			c.Properties = append(c.Properties, *prop)
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("没有日历内容")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("组件 %s 缺少 END", stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+// Get 返回第一个同名属性，不存在时返回 nil
+func (c *Component) Get(name string) *Property {
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// Text 返回第一个同名文本属性反转义后的值
+func (c *Component) Text(name string) string {
+	if prop := c.Get(name); prop != nil {
+		return UnescapeText(prop.Value)
+	}
+	return ""
+}
+
+// Remove 删除所有同名属性
+func (c *Component) Remove(name string) {
+	kept := c.Properties[:0]
+	for _, prop := range c.Properties {
+		if prop.Name != name {
+			kept = append(kept, prop)
+		}
+	}
+	c.Properties = kept
+}
+
+// Children 返回指定名称的子组件
+func (c *Component) Children(name string) []*Component {
+	var children []*Component
+	for _, child := range c.Components {
+		if child.Name == name {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// UnescapeText 反转义 TEXT 类型的值
+func UnescapeText(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// SplitText 拆分以逗号分隔的多值文本，例如 CATEGORIES
+func SplitText(value string) []string {
+	var parts []string
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			b.WriteByte(value[i])
+			b.WriteByte(value[i+1])
+			i++
+		case value[i] == ',':
+			parts = append(parts, UnescapeText(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return append(parts, UnescapeText(b.String()))
+}
+
+// line 展开后的内容行及其起始行号
+type line struct {
+	number int
+	text   string
+}
+
+// unfoldLines 读取并展开折行，兼容只使用 LF 换行的文件
+func unfoldLines(r io.Reader) ([]line, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []line
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if number == 1 {
+			text = string(bytes.TrimPrefix([]byte(text), []byte("\xef\xbb\xbf")))
+		}
+		if text == "" {
+			continue
+		}
+		if (text[0] == ' ' || text[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		lines = append(lines, line{number: number, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// joinParamValues 将多个参数值合并为带引号的列表，编码时原样输出
+func joinParamValues(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = `"` + value + `"`
+	}
+	return strings.Join(quoted, ",")
+}
+
+// parseContentLine 解析 name *(";" param) ":" value 格式的内容行
+func parseContentLine(text string) (*Property, error) {
+	prop := &Property{}
+
+	// 属性名
+	i := strings.IndexAny(text, ";:")
+	if i <= 0 {
+		return nil, errors.New("内容行格式错误")
+	}
+	prop.Name = strings.ToUpper(text[:i])
+
+	// 参数，值可能带引号，引号内可以出现 ; 和 :
+	for text[i] == ';' {
+		i++
+		eq := strings.IndexByte(text[i:], '=')
+		if eq <= 0 {
+			return nil, errors.New("属性参数格式错误")
+		}
+		name := strings.ToUpper(text[i : i+eq])
+		i += eq + 1
+
+		// 参数可以有多个以逗号分隔的值，如 DELEGATED-TO="mailto:a","mailto:b"
+		var values []string
+		for {
+			if i < len(text) && text[i] == '"' {
+				end := strings.IndexByte(text[i+1:], '"')
+				if end < 0 {
+					return nil, errors.New("属性参数缺少结束引号")
+				}
+				values = append(values, text[i+1:i+1+end])
+				i += end + 2
+			} else {
+				end := strings.IndexAny(text[i:], ";:,")
+				if end < 0 {
+					return nil, errors.New("内容行缺少值")
+				}
+				values = append(values, text[i:i+end])
+				i += end
+			}
+			if i >= len(text) || text[i] != ',' {
+				break
+			}
+			i++
+		}
+		value := values[0]
+		if len(values) > 1 {
+			value = joinParamValues(values)
+		}
+		if prop.Params == nil {
+			prop.Params = make(map[string]string)
+		}
+		prop.Params[name] = value
+
+		if i >= len(text) {
+			return nil, errors.New("内容行缺少值")
+		}
+	}
+
+	if text[i] != ':' {
+		return nil, errors.New("内容行格式错误")
+	}
+	prop.Value = text[i+1:]
+	return prop, nil
+}
@@ -0,0 +1,174 @@
+// Package ical 实现 iCalendar（RFC 5545）格式的编码和解析
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 内容行最大长度（字节，不含换行），超过后需要折行
+const maxLineOctets = 75
+
+// Property 组件属性，例如 SUMMARY:买牛奶
+type Property struct {
+	Name   string
+	Params map[string]string // 属性参数，例如 TZID、VALUE
+	Value  string            // 已编码的值，文本值需要先经过 EscapeText
+}
+
+// Component 日历组件，例如 VCALENDAR、VTODO、VEVENT
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+	Line       int // 解析时 BEGIN 所在的行号，编码时忽略
+}
+
+// NewComponent 创建组件
+func NewComponent(name string) *Component {
+	return &Component{Name: name}
+}
+
+// NewCalendar 创建包含必要属性的 VCALENDAR 组件
+func NewCalendar(prodID, name string) *Component {
+	cal := NewComponent("VCALENDAR")
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", prodID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	if name != "" {
+		cal.AddText("X-WR-CALNAME", name)
+	}
+	return cal
+}
+
+// Add 添加原样输出的属性
+func (c *Component) Add(name, value string) {
+	c.Properties = append(c.Properties, Property{Name: name, Value: value})
+}
+
+// AddParams 添加带参数的属性
+func (c *Component) AddParams(name, value string, params map[string]string) {
+	c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// AddText 添加文本属性，会对特殊字符转义
+func (c *Component) AddText(name, text string) {
+	c.Add(name, EscapeText(text))
+}
+
+// AddDateTime 添加 UTC 时间属性
+func (c *Component) AddDateTime(name string, t time.Time) {
+	c.Add(name, FormatDateTime(t))
+}
+
+// AddComponent 添加子组件
+func (c *Component) AddComponent(child *Component) {
+	c.Components = append(c.Components, child)
+}
+
+// Encode 将组件编码为 iCalendar 文本，使用 CRLF 换行并按规范折行
+func Encode(w io.Writer, c *Component) error {
+	bw := bufio.NewWriter(w)
+	if err := encodeComponent(bw, c); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func encodeComponent(w *bufio.Writer, c *Component) error {
+	if err := writeLine(w, "BEGIN:"+c.Name); err != nil {
+		return err
+	}
+	for _, prop := range c.Properties {
+		if err := writeLine(w, formatProperty(&prop)); err != nil {
+			return err
+		}
+	}
+	for _, child := range c.Components {
+		if err := encodeComponent(w, child); err != nil {
+			return err
+		}
+	}
+	return writeLine(w, "END:"+c.Name)
+}
+
+// formatProperty 生成未折行的内容行
+func formatProperty(prop *Property) string {
+	var b strings.Builder
+	b.WriteString(prop.Name)
+
+	// 参数按名称排序，保证输出稳定
+	names := make([]string, 0, len(prop.Params))
+	for name := range prop.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString(";")
+		b.WriteString(name)
+		b.WriteString("=")
+		b.WriteString(quoteParam(prop.Params[name]))
+	}
+
+	b.WriteString(":")
+	b.WriteString(prop.Value)
+	return b.String()
+}
+
+// writeLine 写出一个内容行，超过 75 字节时折行，不会拆开多字节字符
+func writeLine(w *bufio.Writer, line string) error {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if _, err := w.WriteString(line[:cut] + "\r\n "); err != nil {
+			return err
+		}
+		line = line[cut:]
+		// 续行以一个空格开头，占用一个字节
+		limit = maxLineOctets - 1
+	}
+	_, err := w.WriteString(line + "\r\n")
+	return err
+}
+
+// EscapeText 转义 TEXT 类型的值
+func EscapeText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(text)
+}
+
+// quoteParam 参数值包含特殊字符时加引号
+func quoteParam(value string) string {
+	// 参数值中不能出现引号，带引号的是解析时合并的多个值
+	if strings.Contains(value, `"`) {
+		return value
+	}
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	return value
+}
+
+// FormatDateTime 格式化为 UTC 的 DATE-TIME 值，例如 20240101T090000Z
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// FormatDate 格式化为 DATE 值，例如 20240101
+func FormatDate(t time.Time) string {
+	return t.Format("20060102")
+}
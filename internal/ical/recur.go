@@ -0,0 +1,107 @@
+package ical
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Recur 重复规则（RRULE），目前只支持按固定间隔重复的简单规则
+type Recur struct {
+	Freq     string // DAILY, WEEKLY, MONTHLY, YEARLY
+	Interval int
+	Count    int       // 0 表示不限
+	Until    time.Time // 零值表示不限
+}
+
+// maxRecurIterations 计算下次重复时最多迭代的次数
+const maxRecurIterations = 100000
+
+// ParseRecur 解析重复规则，包含 BYDAY 等复杂条件时返回错误。
+// 不带时区的 UNTIL 与开始时间相同，按开始时间所在的时区 loc 解释，loc 为空时为 UTC
+func ParseRecur(value string, loc *time.Location) (*Recur, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	parts := parseRuleParts(value)
+	r := &Recur{Freq: parts["FREQ"], Interval: 1}
+	switch r.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, errors.New("不支持的重复频率: " + r.Freq)
+	}
+
+	for key, v := range parts {
+		switch key {
+		case "FREQ", "WKST":
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, errors.New("重复间隔无效: " + v)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, errors.New("重复次数无效: " + v)
+			}
+			r.Count = n
+		case "UNTIL":
+			if t, err := time.Parse(dateTimeLayout+"Z", v); err == nil {
+				r.Until = t
+			} else if t, err := time.ParseInLocation(dateTimeLayout, v, loc); err == nil {
+				r.Until = t
+			} else if t, err := time.ParseInLocation(dateLayout, v, loc); err == nil {
+				// 只有日期时包含当天
+				r.Until = t.AddDate(0, 0, 1).Add(-time.Second)
+			} else {
+				return nil, errors.New("重复截止时间无效: " + v)
+			}
+		default:
+			return nil, errors.New("不支持的重复条件: " + key)
+		}
+	}
+	return r, nil
+}
+
+// Next 返回不早于 after 的下一次重复时间；重复已结束时返回最后一次，ok 为 false
+func (r *Recur) Next(start, after time.Time) (t time.Time, ok bool) {
+	last := start
+	produced := 0
+	for i := 0; i < maxRecurIterations; i++ {
+		occurrence, valid := r.occurrence(start, i)
+		if !valid {
+			continue
+		}
+		if r.Count > 0 && produced >= r.Count {
+			return last, false
+		}
+		produced++
+		if !r.Until.IsZero() && occurrence.After(r.Until) {
+			return last, false
+		}
+		if !occurrence.Before(after) {
+			return occurrence, true
+		}
+		last = occurrence
+	}
+	return last, false
+}
+
+// occurrence 计算第 n 次重复的时间，月末等不存在的日期按规范跳过
+func (r *Recur) occurrence(start time.Time, n int) (time.Time, bool) {
+	step := n * r.Interval
+	var t time.Time
+	switch r.Freq {
+	case "DAILY":
+		return start.AddDate(0, 0, step), true
+	case "WEEKLY":
+		return start.AddDate(0, 0, 7*step), true
+	case "MONTHLY":
+		t = start.AddDate(0, step, 0)
+	case "YEARLY":
+		t = start.AddDate(step, 0, 0)
+	}
+	// AddDate 会把不存在的日期（如 2 月 30 日）顺延到下个月，此时日期与开始日不同
+	return t, t.Day() == start.Day()
+}
@@ -0,0 +1,285 @@
+package ical
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // 运行环境可能没有时区数据库，内置一份保证 TZID 可以解析
+)
+
+const (
+	dateTimeLayout = "20060102T150405"
+	dateLayout     = "20060102"
+)
+
+// Timezones 解析日历中的时间，处理 TZID 引用的 IANA 时区或 VTIMEZONE 定义
+type Timezones struct {
+	zones    map[string]*vtimezone
+	floating *time.Location
+}
+
+// NewTimezones 从日历中读取 VTIMEZONE 定义。
+// 不带时区的浮动时间使用日历的 X-WR-TIMEZONE，没有时使用 floating
+func NewTimezones(cal *Component, floating *time.Location) *Timezones {
+	tz := &Timezones{zones: make(map[string]*vtimezone), floating: floating}
+	if tz.floating == nil {
+		tz.floating = time.UTC
+	}
+	if name := cal.Text("X-WR-TIMEZONE"); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			tz.floating = loc
+		}
+	}
+	for _, c := range cal.Children("VTIMEZONE") {
+		if id := c.Text("TZID"); id != "" {
+			if zone := parseVTimezone(c); zone != nil {
+				tz.zones[id] = zone
+			}
+		}
+	}
+	return tz
+}
+
+// ParseTime 解析 DATE-TIME 或 DATE 类型的属性，allDay 表示值为 DATE（全天）
+func (tz *Timezones) ParseTime(prop *Property) (t time.Time, allDay bool, err error) {
+	value := strings.TrimSpace(prop.Value)
+	allDay = strings.EqualFold(prop.Params["VALUE"], "DATE") || len(value) == len(dateLayout)
+
+	layout := dateTimeLayout
+	if allDay {
+		layout = dateLayout
+	}
+
+	// UTC 时间
+	if !allDay && strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(layout+"Z", value)
+		if err != nil {
+			return time.Time{}, false, errors.New("时间格式错误: " + value)
+		}
+		return t, false, nil
+	}
+
+	// 墙上时间，先按 UTC 解析出年月日时分秒，再换算到所在时区
+	wall, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, false, errors.New("时间格式错误: " + value)
+	}
+	t, err = tz.resolve(prop.Params["TZID"], wall)
+	return t, allDay, err
+}
+
+// resolve 将墙上时间换算到 TZID 指定的时区，优先使用 IANA 时区数据库
+func (tz *Timezones) resolve(tzid string, wall time.Time) (time.Time, error) {
+	if tzid == "" {
+		return inLocation(wall, tz.floating), nil
+	}
+	// 部分客户端会在 TZID 前加 "/" 表示全局唯一
+	if loc, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+		return inLocation(wall, loc), nil
+	}
+	if zone, ok := tz.zones[tzid]; ok {
+		return wall.Add(-time.Duration(zone.offsetAt(wall)) * time.Second), nil
+	}
+	return time.Time{}, errors.New("未知时区: " + tzid)
+}
+
+// inLocation 以指定时区解释墙上时间
+func inLocation(wall time.Time, loc *time.Location) time.Time {
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+}
+
+// vtimezone VTIMEZONE 定义的时区，由若干标准时间和夏令时规则组成
+type vtimezone struct {
+	observances []observance
+}
+
+// observance 时区中的一段规则（STANDARD 或 DAYLIGHT）
+type observance struct {
+	offset   int       // TZOFFSETTO，秒
+	start    time.Time // DTSTART，墙上时间
+	until    time.Time // RRULE 的 UNTIL，零值表示不限
+	yearly   bool      // 是否为每年重复的规则
+	month    time.Month
+	weekday  time.Weekday
+	week     int // 第几个星期几，-1 表示最后一个
+	standard bool
+}
+
+// parseVTimezone 解析 VTIMEZONE，无法识别的规则会被忽略
+func parseVTimezone(c *Component) *vtimezone {
+	zone := &vtimezone{}
+	for _, child := range c.Components {
+		if child.Name != "STANDARD" && child.Name != "DAYLIGHT" {
+			continue
+		}
+		offset, ok := parseOffset(child.Text("TZOFFSETTO"))
+		if !ok {
+			continue
+		}
+		start, err := time.Parse(dateTimeLayout, child.Text("DTSTART"))
+		if err != nil {
+			continue
+		}
+		obs := observance{offset: offset, start: start, standard: child.Name == "STANDARD"}
+		if rule := child.Get("RRULE"); rule != nil {
+			parseObservanceRule(&obs, rule.Value)
+		}
+		zone.observances = append(zone.observances, obs)
+	}
+	if len(zone.observances) == 0 {
+		return nil
+	}
+	return zone
+}
+
+// parseObservanceRule 解析形如 FREQ=YEARLY;BYMONTH=3;BYDAY=2SU 的规则
+func parseObservanceRule(obs *observance, value string) {
+	rule := parseRuleParts(value)
+	if rule["FREQ"] != "YEARLY" {
+		return
+	}
+	month, err := strconv.Atoi(rule["BYMONTH"])
+	if err != nil || month < 1 || month > 12 {
+		return
+	}
+	week, weekday, ok := parseByDay(rule["BYDAY"])
+	if !ok || week == 0 {
+		return
+	}
+	if until := rule["UNTIL"]; until != "" {
+		if t, err := time.Parse(dateTimeLayout+"Z", until); err == nil {
+			obs.until = t
+		} else if t, err := time.Parse(dateLayout, until); err == nil {
+			obs.until = t
+		}
+	}
+	obs.yearly = true
+	obs.month = time.Month(month)
+	obs.weekday = weekday
+	obs.week = week
+}
+
+// offsetAt 返回墙上时间所处规则的偏移量：取开始时间不晚于该时间的最近一次切换
+func (z *vtimezone) offsetAt(wall time.Time) int {
+	var best *observance
+	var bestOnset time.Time
+	for i := range z.observances {
+		obs := &z.observances[i]
+		for _, year := range []int{wall.Year(), wall.Year() - 1} {
+			onset, ok := obs.onset(year)
+			if !ok || onset.After(wall) {
+				continue
+			}
+			if best == nil || onset.After(bestOnset) {
+				best, bestOnset = obs, onset
+			}
+		}
+	}
+	if best != nil {
+		return best.offset
+	}
+
+	// 早于所有规则时，优先使用标准时间
+	for _, obs := range z.observances {
+		if obs.standard {
+			return obs.offset
+		}
+	}
+	return z.observances[0].offset
+}
+
+// onset 返回规则在指定年份的切换时间
+func (o *observance) onset(year int) (time.Time, bool) {
+	if !o.yearly {
+		if year != o.start.Year() {
+			// 不重复的规则只在开始时切换一次，早于该年的都视为已生效
+			return o.start, year > o.start.Year()
+		}
+		return o.start, true
+	}
+	if year < o.start.Year() {
+		return time.Time{}, false
+	}
+	onset := nthWeekday(year, o.month, o.weekday, o.week, o.start)
+	if !o.until.IsZero() && onset.After(o.until) {
+		return time.Time{}, false
+	}
+	return onset, true
+}
+
+// nthWeekday 计算某年某月的第 n 个星期几，n 为 -1 表示最后一个，时分秒取自 clock
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int, clock time.Time) time.Time {
+	h, m, s := clock.Clock()
+	if n > 0 {
+		first := time.Date(year, month, 1, h, m, s, 0, time.UTC)
+		diff := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, diff+(n-1)*7)
+	}
+	last := time.Date(year, month+1, 0, h, m, s, 0, time.UTC)
+	diff := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -diff+(n+1)*7)
+}
+
+// parseOffset 解析 +0800、-0500、+053000 格式的偏移量
+func parseOffset(value string) (int, bool) {
+	if len(value) != 5 && len(value) != 7 {
+		return 0, false
+	}
+	sign := 1
+	switch value[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, false
+	}
+	hours, err1 := strconv.Atoi(value[1:3])
+	minutes, err2 := strconv.Atoi(value[3:5])
+	seconds := 0
+	var err3 error
+	if len(value) == 7 {
+		seconds, err3 = strconv.Atoi(value[5:7])
+	}
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, false
+	}
+	return sign * (hours*3600 + minutes*60 + seconds), true
+}
+
+// parseRuleParts 将 RRULE 拆分为键值对
+func parseRuleParts(value string) map[string]string {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(value, ";") {
+		if kv := strings.SplitN(part, "=", 2); len(kv) == 2 {
+			parts[strings.ToUpper(kv[0])] = strings.ToUpper(kv[1])
+		}
+	}
+	return parts
+}
+
+// weekdays BYDAY 中的星期缩写
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseByDay 解析单个 BYDAY 值，例如 2SU、-1SU、MO
+func parseByDay(value string) (int, time.Weekday, bool) {
+	if len(value) < 2 || strings.Contains(value, ",") {
+		return 0, 0, false
+	}
+	weekday, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return 0, 0, false
+	}
+	week := 0
+	if prefix := value[:len(value)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil {
+			return 0, 0, false
+		}
+		week = n
+	}
+	return week, weekday, true
+}
@@ -0,0 +1,148 @@
+// Package importer 提供第三方待办应用导出文件的导入适配器。
+// 每个来源实现 Source 接口并在 init 中注册，导入接口和命令行通过来源名称查找适配器
+package importer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"RemindGo/internal/model"
+)
+
+// 字段长度限制，与创建事项的校验一致
+const (
+	maxTitleLength   = 255
+	maxContentLength = 1000
+	maxProjectLength = 100
+	maxUIDLength     = 255
+)
+
+// Source 导入来源适配器
+type Source interface {
+	// Name 来源名称，作为导入接口的 format 参数
+	Name() string
+	// Parse 解析导出文件，filename 为上传的文件名（可能为空），部分来源用它推断清单
+	Parse(filename string, data []byte) (*Result, error)
+}
+
+// Item 解析出的待导入事项
+type Item struct {
+	Line    int        // 所在行号（JSON 为记录序号），从1开始
+	Todo    model.Todo // ExternalID 为空时按内容去重
+	Project string     // 清单名称，不存在时自动创建
+}
+
+// Result 解析结果
+type Result struct {
+	Total    int // 读取到的事项数
+	Items    []Item
+	Skipped  []model.ImportError // 无法导入的记录及原因
+	Warnings []model.ImportError // 已导入但有信息丢失的记录
+}
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = make(map[string]Source)
+)
+
+// Register 注册导入来源，名称重复时会 panic
+func Register(source Source) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	name := source.Name()
+	if _, ok := sources[name]; ok {
+		panic("importer: 重复注册导入来源 " + name)
+	}
+	sources[name] = source
+}
+
+// Lookup 按名称查找导入来源
+func Lookup(name string) (Source, bool) {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	source, ok := sources[strings.ToLower(name)]
+	return source, ok
+}
+
+// Names 已注册的导入来源名称
+func Names() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// skip 记录无法导入的记录
+func (r *Result) skip(line int, field, msg string) {
+	r.Skipped = append(r.Skipped, model.ImportError{Line: line, Field: field, Msg: msg})
+}
+
+// warn 记录导入时丢失的信息
+func (r *Result) warn(line int, field, msg string) {
+	r.Warnings = append(r.Warnings, model.ImportError{Line: line, Field: field, Msg: msg})
+}
+
+// add 规范化并加入待导入事项：截断过长的字段，没有标题的记录会被跳过
+func (r *Result) add(item Item) {
+	todo := &item.Todo
+	todo.Title = strings.TrimSpace(todo.Title)
+	if todo.Title == "" {
+		r.skip(item.Line, "title", "缺少标题")
+		return
+	}
+	if utf8.RuneCountInString(todo.Title) > maxTitleLength {
+		todo.Title = string([]rune(todo.Title)[:maxTitleLength])
+		r.warn(item.Line, "title", "标题超过255个字符，已截断")
+	}
+	if utf8.RuneCountInString(todo.Content) > maxContentLength {
+		todo.Content = string([]rune(todo.Content)[:maxContentLength])
+		r.warn(item.Line, "content", "内容超过1000个字符，已截断")
+	}
+	item.Project = strings.TrimSpace(item.Project)
+	if utf8.RuneCountInString(item.Project) > maxProjectLength {
+		item.Project = string([]rune(item.Project)[:maxProjectLength])
+		r.warn(item.Line, "project", "清单名称超过100个字符，已截断")
+	}
+	if len(todo.ExternalID) > maxUIDLength {
+		todo.ExternalID = ""
+	}
+
+	// 状态与完成时间保持一致
+	if todo.Status == 1 && todo.CompletedAt == nil {
+		now := time.Now()
+		todo.CompletedAt = &now
+	} else if todo.Status == 0 {
+		todo.CompletedAt = nil
+	}
+	r.Items = append(r.Items, item)
+}
+
+// withLabels RemindGo 没有标签，标签以 #标签 的形式追加到内容末尾
+func withLabels(content string, labels []string) string {
+	var tags []string
+	for _, label := range labels {
+		label = strings.Join(strings.Fields(label), "_")
+		if label != "" {
+			tags = append(tags, "#"+label)
+		}
+	}
+	if len(tags) == 0 {
+		return content
+	}
+	if content != "" {
+		content += "\n\n"
+	}
+	return content + strings.Join(tags, " ")
+}
+
+// endOfDay 只有日期的截止时间截止到当天结束
+func endOfDay(t time.Time) time.Time {
+	return t.AddDate(0, 0, 1).Add(-time.Second)
+}
@@ -0,0 +1,231 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"RemindGo/internal/model"
+)
+
+func init() {
+	Register(msTodo{})
+}
+
+// msTodo 导入 Microsoft To Do 的导出文件，格式为 Microsoft Graph 中 todoTaskList 和 todoTask 的 JSON
+type msTodo struct{}
+
+// Name 来源名称
+func (msTodo) Name() string {
+	return "mstodo"
+}
+
+// msTodoDateTime Graph 中的 dateTimeTimeZone，dateTime 不带时区
+type msTodoDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// msTodoTask Graph 中的 todoTask
+type msTodoTask struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Body  *struct {
+		Content     string `json:"content"`
+		ContentType string `json:"contentType"`
+	} `json:"body"`
+	Importance        string          `json:"importance"` // low, normal, high
+	Status            string          `json:"status"`     // notStarted, inProgress, completed, waitingOnOthers, deferred
+	DueDateTime       *msTodoDateTime `json:"dueDateTime"`
+	CompletedDateTime *msTodoDateTime `json:"completedDateTime"`
+	CreatedDateTime   string          `json:"createdDateTime"`
+	Categories        []string        `json:"categories"`
+	Recurrence        json.RawMessage `json:"recurrence"`
+	ChecklistItems    []struct {
+		DisplayName string `json:"displayName"`
+		IsChecked   bool   `json:"isChecked"`
+	} `json:"checklistItems"`
+}
+
+// msTodoList Graph 中的 todoTaskList，tasks 为列表中的事项
+type msTodoList struct {
+	DisplayName       string       `json:"displayName"`
+	WellknownListName string       `json:"wellknownListName"` // defaultList 为默认的「任务」列表
+	Tasks             []msTodoTask `json:"tasks"`
+}
+
+// Parse 接受列表数组、{"lists": [...]}，或 Graph 接口返回的 {"value": [...]}（列表或事项均可）
+func (m msTodo) Parse(filename string, data []byte) (*Result, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+
+	var lists []msTodoList
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &lists); err != nil {
+			return nil, err
+		}
+	} else {
+		var doc struct {
+			Lists []msTodoList    `json:"lists"`
+			Value json.RawMessage `json:"value"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		lists = doc.Lists
+		if len(doc.Value) > 0 {
+			var values []struct {
+				msTodoList
+				Title string `json:"title"`
+			}
+			if err := json.Unmarshal(doc.Value, &values); err != nil {
+				return nil, err
+			}
+			// value 中的元素有 title 时是事项，否则是列表
+			if len(values) > 0 && values[0].Title != "" {
+				var tasks []msTodoTask
+				if err := json.Unmarshal(doc.Value, &tasks); err != nil {
+					return nil, err
+				}
+				lists = append(lists, msTodoList{Tasks: tasks})
+			} else {
+				for _, value := range values {
+					lists = append(lists, value.msTodoList)
+				}
+			}
+		}
+	}
+	if len(lists) == 0 {
+		return nil, errors.New("没有找到 Microsoft To Do 的列表或任务")
+	}
+
+	result := &Result{}
+	line := 0
+	for _, list := range lists {
+		project := list.DisplayName
+		if list.WellknownListName == "defaultList" {
+			project = ""
+		}
+		for _, task := range list.Tasks {
+			line++
+			result.Total++
+			m.addTask(result, line, project, &task)
+		}
+	}
+	return result, nil
+}
+
+// addTask 转换单个事项，检查项对应 RemindGo 的检查项，分类以标签形式追加到内容
+func (msTodo) addTask(result *Result, line int, project string, task *msTodoTask) {
+	todo := model.Todo{
+		Title:    task.Title,
+		Priority: msTodoPriority(task.Importance),
+	}
+	if task.ID != "" {
+		todo.ExternalID = "mstodo-" + task.ID
+	}
+	if task.Body != nil {
+		content := strings.TrimSpace(task.Body.Content)
+		if strings.EqualFold(task.Body.ContentType, "html") && content != "" {
+			result.warn(line, "body", "HTML 格式的备注按原文导入")
+		}
+		todo.Content = content
+	}
+	todo.Content = withLabels(todo.Content, task.Categories)
+
+	if task.DueDateTime != nil {
+		// To Do 的截止时间只有日期，截止到当天结束
+		due, err := msTodoParseTime(task.DueDateTime)
+		if err != nil {
+			result.warn(line, "dueDateTime", err.Error()+"，已忽略截止时间")
+		} else {
+			due = endOfDay(time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, due.Location()))
+			todo.Deadline = &due
+		}
+	}
+	if len(task.Recurrence) > 0 && string(task.Recurrence) != "null" {
+		result.warn(line, "recurrence", "暂不支持重复事项，截止时间取当前这一次")
+	}
+
+	if task.Status == "completed" {
+		todo.Status = 1
+		if task.CompletedDateTime != nil {
+			if completedAt, err := msTodoParseTime(task.CompletedDateTime); err == nil {
+				todo.CompletedAt = &completedAt
+			}
+		}
+	}
+	if createdAt, err := time.Parse(time.RFC3339, task.CreatedDateTime); err == nil {
+		todo.CreatedAt = createdAt
+	}
+
+	for i, item := range task.ChecklistItems {
+		title := strings.TrimSpace(item.DisplayName)
+		if title == "" {
+			continue
+		}
+		if len([]rune(title)) > maxTitleLength {
+			title = string([]rune(title)[:maxTitleLength])
+			result.warn(line, "checklistItems", "检查项超过255个字符，已截断")
+		}
+		todo.ChecklistItems = append(todo.ChecklistItems, model.ChecklistItem{
+			Title:     title,
+			Done:      item.IsChecked,
+			SortOrder: i,
+		})
+	}
+
+	result.add(Item{Line: line, Todo: todo, Project: project})
+}
+
+// msTodoPriority 转换重要性，To Do 只有普通和重要两级
+func msTodoPriority(importance string) int {
+	switch strings.ToLower(importance) {
+	case "high":
+		return model.PriorityHigh
+	case "low":
+		return model.PriorityLow
+	}
+	return model.PriorityNone
+}
+
+// msTodoTimeZones 常见的 Windows 时区名称，其余名称按 IANA 时区解析
+var msTodoTimeZones = map[string]string{
+	"UTC":                       "UTC",
+	"China Standard Time":       "Asia/Shanghai",
+	"Taipei Standard Time":      "Asia/Taipei",
+	"Tokyo Standard Time":       "Asia/Tokyo",
+	"Korea Standard Time":       "Asia/Seoul",
+	"Singapore Standard Time":   "Asia/Singapore",
+	"India Standard Time":       "Asia/Kolkata",
+	"GMT Standard Time":         "Europe/London",
+	"W. Europe Standard Time":   "Europe/Berlin",
+	"Romance Standard Time":     "Europe/Paris",
+	"Eastern Standard Time":     "America/New_York",
+	"Central Standard Time":     "America/Chicago",
+	"Mountain Standard Time":    "America/Denver",
+	"Pacific Standard Time":     "America/Los_Angeles",
+	"AUS Eastern Standard Time": "Australia/Sydney",
+}
+
+// msTodoParseTime 按 timeZone 解释 dateTime，无法识别的时区返回错误
+func msTodoParseTime(value *msTodoDateTime) (time.Time, error) {
+	name := value.TimeZone
+	if name == "" {
+		name = "UTC"
+	}
+	if iana, ok := msTodoTimeZones[name]; ok {
+		name = iana
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Time{}, errors.New("无法识别的时区 " + strconv.Quote(value.TimeZone))
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05.9999999", value.DateTime, loc)
+	if err != nil {
+		return time.Time{}, errors.New("无法识别的时间 " + strconv.Quote(value.DateTime))
+	}
+	return t, nil
+}
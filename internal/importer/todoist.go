@@ -0,0 +1,342 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"RemindGo/internal/model"
+)
+
+func init() {
+	Register(todoist{})
+}
+
+// todoist 导入 Todoist 的导出文件：清单导出的 CSV，或备份/同步接口的 JSON
+type todoist struct{}
+
+// Name 来源名称
+func (todoist) Name() string {
+	return "todoist"
+}
+
+// Parse 按内容判断是 JSON 还是 CSV
+func (t todoist) Parse(filename string, data []byte) (*Result, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return t.parseJSON(data)
+	}
+	return t.parseCSV(filename, data)
+}
+
+// todoistFilePattern 备份中的 CSV 文件名形如 `工作 [2203306141].csv`
+var todoistFilePattern = regexp.MustCompile(`\s*\[\d+\]$`)
+
+// todoistLabelPattern CSV 中标签以 @标签 的形式写在标题里
+var todoistLabelPattern = regexp.MustCompile(`(^|\s)@(\S+)`)
+
+// parseCSV 解析清单导出的 CSV，清单名称取自文件名。
+// TYPE 为 task 的行是事项，note 是上一个事项的评论，section 等其他类型会被忽略
+func (todoist) parseCSV(filename string, data []byte) (*Result, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("缺少表头")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["CONTENT"]; !ok {
+		return nil, errors.New("缺少 CONTENT 列，不是 Todoist 导出的 CSV")
+	}
+
+	project := ""
+	if filename != "" {
+		project = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		project = todoistFilePattern.ReplaceAllString(project, "")
+		if strings.EqualFold(project, "Inbox") {
+			project = ""
+		}
+	}
+
+	result := &Result{}
+	var current *Item
+	var currentLabels []string
+	// 评论追加在描述之后，标签最后追加
+	flush := func() {
+		if current != nil {
+			current.Todo.Content = withLabels(current.Todo.Content, currentLabels)
+			result.add(*current)
+			current = nil
+		}
+	}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		switch strings.ToLower(field("TYPE")) {
+		case "task", "":
+		case "note":
+			if current != nil && field("CONTENT") != "" {
+				if current.Todo.Content != "" {
+					current.Todo.Content += "\n\n"
+				}
+				current.Todo.Content += field("CONTENT")
+			}
+			continue
+		default:
+			continue
+		}
+
+		flush()
+		result.Total++
+		title, labels := todoistLabels(field("CONTENT"))
+		current = &Item{
+			Line: line,
+			Todo: model.Todo{
+				Title:    title,
+				Content:  field("DESCRIPTION"),
+				Priority: todoistPriority(field("PRIORITY"), false),
+			},
+			Project: project,
+		}
+		currentLabels = labels
+
+		if date := field("DATE"); date != "" {
+			deadline, err := todoistParseDate(date, field("TIMEZONE"))
+			if err != nil {
+				result.warn(line, "DATE", err.Error()+"，已忽略截止时间")
+			} else {
+				current.Todo.Deadline = &deadline
+			}
+		}
+	}
+	flush()
+	return result, nil
+}
+
+// todoistLabels 从标题中取出 @标签
+func todoistLabels(content string) (string, []string) {
+	var labels []string
+	for _, m := range todoistLabelPattern.FindAllStringSubmatch(content, -1) {
+		labels = append(labels, m[2])
+	}
+	title := todoistLabelPattern.ReplaceAllString(content, "$1")
+	return strings.Join(strings.Fields(title), " "), labels
+}
+
+// todoistParseDate 解析 CSV 中的日期。只有日期时截止到当天结束；
+// 重复日期（如 every monday）等自然语言描述无法识别
+func todoistParseDate(value, timezone string) (time.Time, error) {
+	loc := time.UTC
+	if timezone != "" {
+		if l, err := time.LoadLocation(timezone); err == nil {
+			loc = l
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02", "Jan 2 2006", "2 Jan 2006", "January 2 2006", "2 January 2006"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return endOfDay(t), nil
+		}
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04:05", "Jan 2 2006 15:04", "2 Jan 2006 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	if strings.HasPrefix(strings.ToLower(value), "every") {
+		return time.Time{}, errors.New("暂不支持重复日期 " + strconv.Quote(value))
+	}
+	return time.Time{}, errors.New("无法识别的日期 " + strconv.Quote(value))
+}
+
+// todoistPriority 转换优先级。CSV 中 1 为最高（p1）、4 为默认（p4）；
+// 接口和备份的 JSON 中相反，4 为最高、1 为默认
+func todoistPriority(value string, api bool) int {
+	p, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || p < 1 || p > 4 {
+		return model.PriorityNone
+	}
+	if !api {
+		p = 5 - p
+	}
+	switch p {
+	case 4:
+		return model.PriorityUrgent
+	case 3:
+		return model.PriorityHigh
+	case 2:
+		return model.PriorityMedium
+	}
+	return model.PriorityNone
+}
+
+// todoistID Todoist 的标识在旧版接口中是数字，新版中是字符串
+type todoistID string
+
+// UnmarshalJSON 同时接受数字和字符串
+func (id *todoistID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*id = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = todoistID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = todoistID(n.String())
+	return nil
+}
+
+// todoistTask JSON 中的事项，兼容同步接口（items）和 REST 接口（tasks）的字段
+type todoistTask struct {
+	ID          todoistID `json:"id"`
+	Content     string    `json:"content"`
+	Description string    `json:"description"`
+	ProjectID   todoistID `json:"project_id"`
+	Priority    int       `json:"priority"`
+	Labels      []string  `json:"labels"`
+	Due         *struct {
+		Date        string `json:"date"`
+		Datetime    string `json:"datetime"`
+		Timezone    string `json:"timezone"`
+		IsRecurring bool   `json:"is_recurring"`
+		String      string `json:"string"`
+	} `json:"due"`
+	Checked     bool   `json:"checked"`
+	IsCompleted bool   `json:"is_completed"`
+	CompletedAt string `json:"completed_at"`
+	AddedAt     string `json:"added_at"`
+	CreatedAt   string `json:"created_at"`
+	IsDeleted   bool   `json:"is_deleted"`
+}
+
+// todoistBackup 同步接口或备份的 JSON
+type todoistBackup struct {
+	Projects []struct {
+		ID           todoistID `json:"id"`
+		Name         string    `json:"name"`
+		InboxProject bool      `json:"inbox_project"`
+	} `json:"projects"`
+	Items []todoistTask `json:"items"`
+	Tasks []todoistTask `json:"tasks"`
+}
+
+// parseJSON 解析同步接口或备份的 JSON 对象，也接受 REST 接口返回的事项数组
+func (todoist) parseJSON(data []byte) (*Result, error) {
+	var backup todoistBackup
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &backup.Items); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, err
+	}
+	tasks := append(backup.Items, backup.Tasks...)
+
+	projects := make(map[todoistID]string, len(backup.Projects))
+	for _, project := range backup.Projects {
+		if !project.InboxProject {
+			projects[project.ID] = project.Name
+		}
+	}
+
+	result := &Result{}
+	for i, task := range tasks {
+		line := i + 1
+		if task.IsDeleted {
+			continue
+		}
+		result.Total++
+
+		todo := model.Todo{
+			Title:    task.Content,
+			Content:  withLabels(task.Description, task.Labels),
+			Priority: todoistPriority(strconv.Itoa(task.Priority), true),
+		}
+		if task.ID != "" {
+			todo.ExternalID = "todoist-" + string(task.ID)
+		}
+		if task.Due != nil {
+			deadline, err := todoistDue(task.Due.Date, task.Due.Datetime, task.Due.Timezone)
+			if err != nil {
+				result.warn(line, "due", err.Error()+"，已忽略截止时间")
+			} else {
+				todo.Deadline = &deadline
+			}
+			if task.Due.IsRecurring {
+				result.warn(line, "due", "暂不支持重复事项，截止时间取当前这一次")
+			}
+		}
+		if task.Checked || task.IsCompleted {
+			todo.Status = 1
+			if t, err := time.Parse(time.RFC3339, task.CompletedAt); err == nil {
+				todo.CompletedAt = &t
+			}
+		}
+		for _, value := range []string{task.AddedAt, task.CreatedAt} {
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				todo.CreatedAt = t
+				break
+			}
+		}
+
+		result.add(Item{Line: line, Todo: todo, Project: projects[task.ProjectID]})
+	}
+	return result, nil
+}
+
+// todoistDue 解析 JSON 中的截止时间：只有日期时截止到当天结束，
+// 不带时区的时间按 timezone 解释（没有时按 UTC）
+func todoistDue(date, datetime, timezone string) (time.Time, error) {
+	if datetime != "" {
+		date = datetime
+	}
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		return t, nil
+	}
+	loc := time.UTC
+	if timezone != "" {
+		if l, err := time.LoadLocation(timezone); err == nil {
+			loc = l
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", date, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", date, loc); err == nil {
+		return endOfDay(t), nil
+	}
+	return time.Time{}, errors.New("无法识别的日期 " + strconv.Quote(date))
+}
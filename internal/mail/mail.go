@@ -0,0 +1,125 @@
+// Package mail 发送邮件，支持 SMTP 和只写日志两种方式
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message 邮件内容，同时包含 HTML 和纯文本两种格式
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+	Headers map[string]string // 额外的邮件头，如 List-Unsubscribe
+}
+
+// Sender 邮件发送器
+type Sender interface {
+	Send(msg *Message) error
+}
+
+// SMTP 通过 SMTP 服务器发送邮件
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string // 为空时不认证
+	Password string
+	From     string
+}
+
+// Send 发送邮件
+func (s *SMTP) Send(msg *Message) error {
+	if msg.To == "" {
+		return errors.New("收件人为空")
+	}
+	data, err := s.build(msg)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	return smtp.SendMail(s.Host+":"+strconv.Itoa(s.Port), auth, s.From, []string{msg.To}, data)
+}
+
+// build 生成 multipart/alternative 格式的邮件
+func (s *SMTP) build(msg *Message) ([]byte, error) {
+	boundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	headers := map[string]string{
+		"From":         s.From,
+		"To":           msg.To,
+		"Subject":      mime.QEncoding.Encode("UTF-8", msg.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"MIME-Version": "1.0",
+		"Content-Type": `multipart/alternative; boundary="` + boundary + `"`,
+	}
+	for name, value := range msg.Headers {
+		headers[name] = value
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// 去掉换行，避免邮件头注入
+		value := strings.NewReplacer("\r", "", "\n", "").Replace(headers[name])
+		buf.WriteString(name + ": " + value + "\r\n")
+	}
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		buf.WriteString("--" + boundary + "\r\n")
+		buf.WriteString("Content-Type: " + part.contentType + "\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		w := quotedprintable.NewWriter(&buf)
+		if _, err := w.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString("--" + boundary + "--\r\n")
+	return buf.Bytes(), nil
+}
+
+// randomBoundary 生成 multipart 分隔符
+func randomBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "remindgo-" + hex.EncodeToString(b), nil
+}
+
+// Log 不发送邮件，只写日志，用于开发环境或未配置 SMTP 时
+type Log struct{}
+
+// Send 记录邮件的收件人和主题
+func (Log) Send(msg *Message) error {
+	log.Printf("Mail to %s (not sent, SMTP not configured): %s", msg.To, msg.Subject)
+	return nil
+}
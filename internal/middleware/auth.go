@@ -103,6 +103,7 @@ func NewJWTMiddleware(db *gorm.DB) (*jwt.HertzJWTMiddleware, error) {
 						ID:        dbUser.ID,
 						Username:  dbUser.Username,
 						Email:     dbUser.Email,
+						TimeZone:  dbUser.TimeZone,
 						CreatedAt: dbUser.CreatedAt.Unix(),
 					},
 				},
@@ -172,3 +173,16 @@ func GetUsername(c *app.RequestContext) (string, error) {
 
 	return "", errors.New("未找到用户名")
 }
+
+// QueryToken 请求没有 Authorization 头时，使用 access_token 查询参数作为 Bearer Token。
+// 浏览器的 EventSource 和 WebSocket 无法设置请求头，只用于实时推送接口，需放在JWT中间件之前
+func QueryToken() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if len(c.GetHeader("Authorization")) == 0 {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next(ctx)
+	}
+}
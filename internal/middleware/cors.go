@@ -12,10 +12,12 @@ func CORS() app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, Idempotency-Key")
+		c.Header("Access-Control-Expose-Headers", "ETag, Location, Idempotent-Replayed")
 		c.Header("Access-Control-Max-Age", "86400")
 
-		if string(c.Method()) == "OPTIONS" {
+		// 只拦截跨域预检请求，CalDAV 客户端的 OPTIONS 请求需要交给处理器
+		if string(c.Method()) == "OPTIONS" && len(c.GetHeader("Access-Control-Request-Method")) > 0 {
 			c.AbortWithStatus(consts.StatusNoContent)
 			return
 		}
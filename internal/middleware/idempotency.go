@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"RemindGo/internal/model"
+	"RemindGo/internal/service"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// IdempotencyKeyHeader 幂等键请求头
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Idempotency 幂等键中间件，需要放在JWT中间件之后。请求带有 Idempotency-Key 头时，
+// 同一用户使用相同的键重试会直接返回第一次请求的响应（包括 ETag、Location 等响应头），不会重复执行；
+// 键已用于内容不同的请求时返回 422，第一次请求仍在处理时返回 409。服务器错误的响应不保存，可以重试
+func Idempotency(idempotencyService *service.IdempotencyService) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		key := string(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" {
+			c.Next(ctx)
+			return
+		}
+		userID, err := GetUserID(c)
+		if err != nil {
+			c.Next(ctx)
+			return
+		}
+
+		fingerprint := service.RequestFingerprint(string(c.Method()), string(c.Request.URI().RequestURI()), c.Request.Body())
+		record, err := idempotencyService.Begin(userID, key, fingerprint)
+		if err != nil {
+			status := consts.StatusInternalServerError
+			switch err.Error() {
+			case "幂等键无效":
+				status = consts.StatusBadRequest
+			case "幂等键已用于其他请求":
+				status = consts.StatusUnprocessableEntity
+			case "相同幂等键的请求正在处理":
+				status = consts.StatusConflict
+			}
+			c.AbortWithStatusJSON(status, model.BaseResponse{
+				Status: status,
+				Msg:    err.Error(),
+				Data:   nil,
+			})
+			return
+		}
+
+		// 重试，返回第一次请求的响应
+		if record.StatusCode > 0 {
+			for name, value := range service.ReplayHeaders(record) {
+				c.Header(name, value)
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, record.ContentType, []byte(record.Response))
+			c.Abort()
+			return
+		}
+
+		c.Next(ctx)
+
+		status := c.Response.StatusCode()
+		if status >= consts.StatusInternalServerError {
+			err = idempotencyService.Release(record)
+		} else {
+			headers := make(map[string]string)
+			for _, name := range service.IdempotencyReplayHeaders {
+				if value := c.Response.Header.Get(name); value != "" {
+					headers[name] = value
+				}
+			}
+			err = idempotencyService.Complete(record, status, string(c.Response.Header.ContentType()), headers, c.Response.Body())
+		}
+		if err != nil {
+			log.Printf("Failed to save idempotency key: %v", err)
+		}
+	}
+}
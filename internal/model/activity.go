@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// 动态类型
+const (
+	ActivityCreated   = "created"   // 创建
+	ActivityUpdated   = "updated"   // 修改字段
+	ActivityCompleted = "completed" // 完成
+	ActivityReopened  = "reopened"  // 重新打开
+	ActivityDeleted   = "deleted"   // 移入回收站
+	ActivityRestored  = "restored"  // 从回收站恢复
+	ActivityPurged    = "purged"    // 永久删除
+)
+
+// TodoActivity 待办事项动态，只追加不修改
+type TodoActivity struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	UserID    int64     `json:"-" gorm:"not null;index;index:idx_todo_activities_user_seq,priority:1"` // 事项所有者
+	ActorID   int64     `json:"actor_id" gorm:"not null"`                                              // 操作者，0 表示系统任务
+	TodoID    int64     `json:"todo_id" gorm:"not null;index"`
+	Action    string    `json:"action" gorm:"not null;size:32"`
+	Source    string    `json:"source" gorm:"size:64"`                                                     // 触发来源，例如 batch.complete，为空表示单条操作
+	Changes   string    `json:"-" gorm:"type:text"`                                                        // 字段变更，JSON 格式
+	Seq       int64     `json:"-" gorm:"not null;default:0;index:idx_todo_activities_user_seq,priority:2"` // 用户内的同步序号，按提交顺序递增
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// FieldChange 字段变更前后的值
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ActivityResponse 动态响应
+type ActivityResponse struct {
+	ID        int64         `json:"id"`
+	TodoID    int64         `json:"todo_id"`
+	ActorID   int64         `json:"actor_id"`
+	Action    string        `json:"action"`
+	Source    string        `json:"source"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt int64         `json:"created_at"` // Unix 时间戳
+}
+
+// ActivityListResponse 动态列表响应
+type ActivityListResponse struct {
+	Items      []ActivityResponse `json:"items"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// ActivityQueryParams 动态查询参数
+type ActivityQueryParams struct {
+	Page     int    `query:"page"`      // 页码，从1开始
+	PageSize int    `query:"page_size"` // 每页条数
+	Action   string `query:"action"`    // 按动态类型过滤
+}
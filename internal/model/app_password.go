@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// AppPassword 应用密码，供 CalDAV 等无法使用 JWT 的客户端通过 Basic 认证访问
+type AppPassword struct {
+	ID         int64      `json:"id" gorm:"primary_key"`
+	UserID     int64      `json:"-" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"not null;size:100"`         // 用途说明，例如 "iPhone 提醒事项"
+	TokenHash  string     `json:"-" gorm:"not null;size:64;uniqueIndex"` // 密码的 SHA-256，明文只在创建时返回一次
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAppPasswordRequest 创建应用密码请求
+type CreateAppPasswordRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// AppPasswordResponse 应用密码响应
+type AppPasswordResponse struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Password   string `json:"password,omitempty"` // 仅创建时返回
+	LastUsedAt *int64 `json:"last_used_at"`       // Unix 时间戳
+	CreatedAt  int64  `json:"created_at"`         // Unix 时间戳
+}
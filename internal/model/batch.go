@@ -0,0 +1,101 @@
+package model
+
+import "encoding/json"
+
+// 选择性批量操作类型
+const (
+	BatchActionComplete    = "complete"     // 标记完成
+	BatchActionReopen      = "reopen"       // 重新打开
+	BatchActionDelete      = "delete"       // 移入回收站
+	BatchActionSetDeadline = "set_deadline" // 设置截止时间
+	BatchActionSetFields   = "set_fields"   // 设置多个字段
+)
+
+// 批量操作单项结果状态
+const (
+	BatchItemOK       = "ok"        // 已处理
+	BatchItemSkipped  = "skipped"   // 无需处理，例如已是目标状态
+	BatchItemNotFound = "not_found" // 不存在或不属于当前用户
+	BatchItemBlocked  = "blocked"   // 存在未完成的前置事项，未完成
+)
+
+// TodoFilter 待办事项过滤条件，语法与列表接口的查询参数一致
+type TodoFilter struct {
+	Status    string `json:"status"`     // all, pending, completed
+	Keyword   string `json:"keyword"`    // 搜索关键词
+	ProjectID int64  `json:"project_id"` // 按清单过滤
+	Priority  string `json:"priority"`   // 按优先级过滤，逗号分隔
+	Blocked   string `json:"blocked"`    // true / false
+}
+
+// BatchSetFields 批量设置的字段，未传的字段保持不变
+type BatchSetFields struct {
+	ProjectID             *int64  `json:"project_id"` // 0 表示移出清单
+	Priority              *int    `json:"priority"`
+	Deadline              *string `json:"deadline"` // 空字符串表示清除截止时间
+	ChecklistAutoComplete *bool   `json:"checklist_auto_complete"`
+}
+
+// BatchActionRequest 选择性批量操作请求，ids 与 filter 二选一
+type BatchActionRequest struct {
+	Action   string          `json:"action"`   // complete, reopen, delete, set_deadline, set_fields
+	IDs      []int64         `json:"ids"`      // 指定事项ID
+	Filter   *TodoFilter     `json:"filter"`   // 按条件选择事项
+	Deadline *string         `json:"deadline"` // set_deadline 使用，空字符串表示清除
+	Fields   *BatchSetFields `json:"fields"`   // set_fields 使用
+	Force    bool            `json:"force"`    // complete 使用，允许完成仍有未完成前置事项的事项
+}
+
+// BatchItemResult 批量操作单项结果
+type BatchItemResult struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"` // ok, skipped, not_found, blocked
+}
+
+// BatchActionResponse 选择性批量操作响应
+type BatchActionResponse struct {
+	Action        string            `json:"action"`
+	AffectedCount int64             `json:"affected_count"`
+	UndoToken     string            `json:"undo_token,omitempty"`
+	UndoExpiresAt *int64            `json:"undo_expires_at,omitempty"`
+	Results       []BatchItemResult `json:"results"`
+}
+
+// 多操作批量接口的操作类型
+const (
+	BatchOpCreate = "create" // 创建
+	BatchOpUpdate = "update" // 更新
+	BatchOpDelete = "delete" // 删除（移入回收站）
+	BatchOpToggle = "toggle" // 切换状态
+)
+
+// BatchOperation 多操作批量接口中的单个操作
+type BatchOperation struct {
+	Op     string          `json:"op"`      // create, update, delete, toggle
+	TempID string          `json:"temp_id"` // create 使用，客户端指定的临时ID，后续操作可通过 ref 引用
+	ID     int64           `json:"id"`      // update/delete/toggle 使用，目标事项ID
+	Ref    string          `json:"ref"`     // update/delete/toggle 使用，引用前面 create 操作的临时ID，与 id 二选一
+	Force  bool            `json:"force"`   // toggle 和 update 使用，允许完成仍有未完成前置事项的事项
+	Data   json.RawMessage `json:"data"`    // create 对应 CreateTodoRequest，update 对应 UpdateTodoRequest
+}
+
+// BatchOperationsRequest 多操作批量请求，按顺序在同一事务中执行
+type BatchOperationsRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperationItemResult 单个操作的执行结果
+type BatchOperationItemResult struct {
+	Index  int           `json:"index"` // 操作在请求中的序号，从0开始
+	Op     string        `json:"op"`
+	TempID string        `json:"temp_id,omitempty"`
+	ID     int64         `json:"id,omitempty"`
+	OK     bool          `json:"ok"`
+	Msg    string        `json:"msg,omitempty"` // 失败原因
+	Todo   *TodoResponse `json:"todo,omitempty"`
+}
+
+// BatchOperationsResponse 多操作批量响应
+type BatchOperationsResponse struct {
+	Results []BatchOperationItemResult `json:"results"`
+}
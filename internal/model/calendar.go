@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// CalendarFeed 日历订阅，每个用户一个，通过秘密令牌访问
+type CalendarFeed struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	UserID    int64     `json:"-" gorm:"not null;uniqueIndex"`
+	Token     string    `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CalendarFeedResponse 日历订阅响应
+type CalendarFeedResponse struct {
+	URL       string `json:"url"`        // 订阅地址，可直接添加到日历应用
+	CreatedAt int64  `json:"created_at"` // Unix 时间戳
+}
+
+// CalendarFeedParams 日历订阅查询参数
+type CalendarFeedParams struct {
+	Status    string `query:"status"`     // all（默认）, pending, completed
+	ProjectID int64  `query:"project_id"` // 仅包含指定清单
+	Type      string `query:"type"`       // event（默认，生成 VEVENT）, todo（生成 VTODO）
+}
+
+// CalendarObject CalDAV 客户端提交的原始日历数据。返回给客户端时以此为基础，
+// 保留服务端不支持的属性（重复规则、提醒、关联事项和扩展属性等）
+type CalendarObject struct {
+	TodoID    int64     `json:"todo_id" gorm:"primary_key;autoIncrement:false"`
+	UserID    int64     `json:"-" gorm:"not null;index"`
+	Data      string    `json:"-" gorm:"type:mediumtext"` // 客户端提交的 VCALENDAR
+	Version   int64     `json:"version" gorm:"not null"`  // 保存时事项的版本号，事项未再修改时原样返回 Data
+	Snapshot  string    `json:"-" gorm:"type:text"`       // 保存时事项的字段，JSON 格式，用于判断哪些字段之后在服务端被修改
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// ChecklistItem 待办事项下的检查项（子任务）
+type ChecklistItem struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	TodoID    int64     `json:"todo_id" gorm:"not null;index"`
+	Title     string    `json:"title" gorm:"not null;size:255"`
+	Done      bool      `json:"done" gorm:"default:false"`
+	SortOrder int       `json:"sort_order" gorm:"default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateChecklistItemRequest 添加检查项请求
+type CreateChecklistItemRequest struct {
+	Title string `json:"title" binding:"required,min=1,max=255"`
+}
+
+// UpdateChecklistItemRequest 更新检查项请求
+type UpdateChecklistItemRequest struct {
+	Title *string `json:"title" binding:"omitempty,min=1,max=255"`
+}
+
+// ReorderChecklistRequest 检查项排序请求
+type ReorderChecklistRequest struct {
+	ItemIDs []int64 `json:"item_ids" binding:"required"` // 按新顺序排列的全部检查项ID
+}
+
+// ChecklistItemResponse 检查项响应
+type ChecklistItemResponse struct {
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	Done      bool   `json:"done"`
+	SortOrder int    `json:"sort_order"`
+	CreatedAt int64  `json:"created_at"` // Unix 时间戳
+	UpdatedAt int64  `json:"updated_at"` // Unix 时间戳
+}
+
+// ChecklistToggleResponse 切换检查项状态响应
+type ChecklistToggleResponse struct {
+	Item ChecklistItemResponse `json:"item"`
+	Todo TodoResponse          `json:"todo"` // 父待办事项（可能因自动完成而改变状态）
+}
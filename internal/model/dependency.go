@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// TodoDependency 待办事项依赖关系：TodoID 在 BlockedByID 完成之前无法完成
+type TodoDependency struct {
+	ID          int64     `json:"id" gorm:"primary_key"`
+	UserID      int64     `json:"-" gorm:"not null;index"`
+	TodoID      int64     `json:"todo_id" gorm:"not null;uniqueIndex:idx_todo_blocked_by"`
+	BlockedByID int64     `json:"blocked_by_id" gorm:"not null;uniqueIndex:idx_todo_blocked_by;index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AddDependencyRequest 添加前置事项请求
+type AddDependencyRequest struct {
+	BlockedByID int64 `json:"blocked_by_id" binding:"required"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// 摘要邮件的发送频率
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestSetting 摘要邮件设置，发送时间按用户时区计算
+type DigestSetting struct {
+	ID               int64      `json:"id" gorm:"primary_key"`
+	UserID           int64      `json:"user_id" gorm:"not null;uniqueIndex"`
+	Frequency        string     `json:"frequency" gorm:"size:16;not null;default:'off'"` // off, daily, weekly
+	SendHour         int        `json:"send_hour" gorm:"not null;default:8"`             // 发送的整点，0-23
+	Weekday          int        `json:"weekday" gorm:"not null;default:1"`               // 每周摘要的发送日，0 为周日
+	UnsubscribeToken string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	LastSentAt       *time.Time `json:"last_sent_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// UpdateDigestSettingRequest 更新摘要设置请求
+type UpdateDigestSettingRequest struct {
+	Frequency *string `json:"frequency" binding:"omitempty,oneof=off daily weekly"`
+	SendHour  *int    `json:"send_hour" binding:"omitempty,min=0,max=23"`
+	Weekday   *int    `json:"weekday" binding:"omitempty,min=0,max=6"`
+}
+
+// DigestSettingResponse 摘要设置响应
+type DigestSettingResponse struct {
+	Frequency  string `json:"frequency"`
+	SendHour   int    `json:"send_hour"`
+	Weekday    int    `json:"weekday"`
+	TimeZone   string `json:"time_zone"`    // 用户时区，在用户信息中修改
+	LastSentAt *int64 `json:"last_sent_at"` // Unix 时间戳
+}
+
+// DigestPreviewParams 预览摘要参数
+type DigestPreviewParams struct {
+	Frequency string `query:"frequency"` // daily, weekly，默认使用当前设置，未开启时为 daily
+	Format    string `query:"format"`    // html, text 直接返回邮件内容，默认返回 JSON
+}
+
+// DigestPreviewResponse 预览摘要响应
+type DigestPreviewResponse struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+	Empty   bool   `json:"empty"` // 没有任何事项时不会发送
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// IdempotencyRecord 幂等键记录，保存使用该键的第一次请求的响应，重试时直接返回
+type IdempotencyRecord struct {
+	ID          int64     `json:"id" gorm:"primary_key"`
+	UserID      int64     `json:"-" gorm:"not null;uniqueIndex:idx_idempotency_user_key,priority:1"`
+	Key         string    `json:"key" gorm:"column:idempotency_key;not null;size:255;uniqueIndex:idx_idempotency_user_key,priority:2"`
+	Fingerprint string    `json:"-" gorm:"not null;size:64"`   // 请求方法、路径和请求体的 SHA-256
+	StatusCode  int       `json:"status_code" gorm:"not null"` // 响应状态码，0 表示请求仍在处理
+	ContentType string    `json:"-" gorm:"size:128"`
+	Headers     string    `json:"-" gorm:"type:text"` // 需要重放的响应头，JSON 对象
+	Response    string    `json:"-" gorm:"type:mediumtext"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"index"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// Project 清单（用于对待办事项分组）
+type Project struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	UserID    int64     `json:"-" gorm:"not null;index"`
+	Name      string    `json:"name" gorm:"not null;size:100"`
+	Color     string    `json:"color" gorm:"size:20"`
+	Archived  bool      `json:"archived" gorm:"default:false;index"`
+	SortOrder int       `json:"sort_order" gorm:"default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateProjectRequest 创建清单请求
+type CreateProjectRequest struct {
+	Name      string `json:"name" binding:"required,min=1,max=100"`
+	Color     string `json:"color" binding:"max=20"` // 例如 #FF8800
+	SortOrder *int   `json:"sort_order"`             // 不传则排在最后
+}
+
+// UpdateProjectRequest 更新清单请求
+type UpdateProjectRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Color     *string `json:"color" binding:"omitempty,max=20"`
+	Archived  *bool   `json:"archived"`
+	SortOrder *int    `json:"sort_order"`
+}
+
+// ProjectResponse 清单响应
+type ProjectResponse struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	Archived  bool   `json:"archived"`
+	SortOrder int    `json:"sort_order"`
+	CreatedAt int64  `json:"created_at"` // Unix 时间戳
+	UpdatedAt int64  `json:"updated_at"` // Unix 时间戳
+}
+
+// ProjectQueryParams 清单查询参数
+type ProjectQueryParams struct {
+	IncludeArchived bool `query:"include_archived"` // 是否包含已归档清单
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// TodoRevision 待办事项内容版本，保存标题和内容的历史快照
+type TodoRevision struct {
+	ID        int64     `json:"id" gorm:"primary_key"`
+	UserID    int64     `json:"-" gorm:"not null;index"`
+	TodoID    int64     `json:"todo_id" gorm:"not null;uniqueIndex:idx_todo_rev"`
+	Rev       int       `json:"rev" gorm:"not null;uniqueIndex:idx_todo_rev"` // 事项内递增的版本号，从1开始
+	Title     string    `json:"title" gorm:"not null;size:255"`
+	Content   string    `json:"content" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RevisionResponse 版本响应
+type RevisionResponse struct {
+	Rev       int    `json:"rev"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	CreatedAt int64  `json:"created_at"` // Unix 时间戳
+}
+
+// RevisionDiffParams 版本比较参数
+type RevisionDiffParams struct {
+	From int `query:"from"` // 起始版本号
+	To   int `query:"to"`   // 目标版本号，不传表示最新版本
+}
+
+// RevisionDiffResponse 版本比较响应
+type RevisionDiffResponse struct {
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	Diff string `json:"diff"` // unified diff 格式文本
+}
@@ -0,0 +1,49 @@
+package model
+
+// 时间序列的分桶粒度
+const (
+	StatsBucketDay   = "day"
+	StatsBucketWeek  = "week" // 周一为一周的开始
+	StatsBucketMonth = "month"
+)
+
+// StatsTimeseriesParams 时间序列统计查询参数
+type StatsTimeseriesParams struct {
+	Bucket    string `query:"bucket"`     // day, week, month，默认 day
+	From      string `query:"from"`       // 开始日期 YYYY-MM-DD，默认按粒度取最近30天、12周或12个月
+	To        string `query:"to"`         // 结束日期 YYYY-MM-DD（包含），默认今天
+	TimeZone  string `query:"tz"`         // IANA 时区，默认使用用户设置的时区
+	ProjectID *int64 `query:"project_id"` // 只统计指定清单
+}
+
+// StatsBucket 单个时间段内新建和完成的事项数
+type StatsBucket struct {
+	Start     string `json:"start"` // 时间段的第一天 YYYY-MM-DD
+	Created   int64  `json:"created"`
+	Completed int64  `json:"completed"`
+}
+
+// StatsHeatmapDay 热力图中单日完成的事项数
+type StatsHeatmapDay struct {
+	Date      string `json:"date"`
+	Completed int64  `json:"completed"`
+}
+
+// StatsTimeseries 时间序列统计，日期均按 time_zone 划分
+type StatsTimeseries struct {
+	Bucket   string        `json:"bucket"`
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	TimeZone string        `json:"time_zone"`
+	Series   []StatsBucket `json:"series"`
+	// 范围内完成的事项从创建到完成的平均耗时（秒），没有完成的事项时为0
+	AvgCompletionSeconds float64 `json:"avg_completion_seconds"`
+	// 范围内完成的事项中按时完成、逾期完成和没有截止时间的数量
+	OnTime     int64 `json:"on_time"`
+	Late       int64 `json:"late"`
+	NoDeadline int64 `json:"no_deadline"`
+	// 连续有完成事项的天数，不受 from/to 限制；今天还没有完成事项时当前连续天数从昨天开始计算
+	CurrentStreak int               `json:"current_streak"`
+	LongestStreak int               `json:"longest_streak"`
+	Heatmap       []StatsHeatmapDay `json:"heatmap"` // 范围内每天完成的事项数
+}
@@ -0,0 +1,29 @@
+package model
+
+// 实时推送的事件类型
+const (
+	ChangeTodoCreated  = "todo.created"
+	ChangeTodoUpdated  = "todo.updated"
+	ChangeTodoToggled  = "todo.toggled"  // 完成或重新打开
+	ChangeTodoDeleted  = "todo.deleted"  // 移入回收站或永久删除，action 区分
+	ChangeTodoRestored = "todo.restored" // 从回收站恢复
+	ChangeReset        = "reset"         // 积压的变更过多，客户端需要重新拉取全部数据
+)
+
+// ChangeEvent 实时推送的变更。ID 为动态ID，按时间递增，断线重连时通过 Last-Event-ID 续传
+type ChangeEvent struct {
+	ID        int64         `json:"id"`
+	Type      string        `json:"type"`
+	TodoID    int64         `json:"todo_id,omitempty"`
+	Action    string        `json:"action,omitempty"` // 动态类型
+	Source    string        `json:"source,omitempty"` // 触发来源，例如 batch.complete
+	ActorID   int64         `json:"actor_id,omitempty"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+	Todo      *TodoResponse `json:"todo,omitempty"`       // 事项的当前状态，已永久删除时为空
+	CreatedAt int64         `json:"created_at,omitempty"` // Unix 时间戳
+}
+
+// StreamParams 实时推送参数
+type StreamParams struct {
+	LastEventID int64 `query:"last_event_id"` // 从该事件之后续传，优先使用 Last-Event-ID 请求头
+}
@@ -0,0 +1,25 @@
+package model
+
+// SyncParams 增量同步参数
+type SyncParams struct {
+	Since string `query:"since"` // 上次同步返回的令牌，为空表示全量同步
+	Limit int    `query:"limit"` // 每次最多处理的变更数或事项数
+}
+
+// SyncTombstone 已删除事项的墓碑
+type SyncTombstone struct {
+	ID        int64 `json:"id"`
+	Purged    bool  `json:"purged"`     // 是否已永久删除，为 false 表示在回收站中
+	DeletedAt int64 `json:"deleted_at"` // Unix 时间戳
+}
+
+// SyncResponse 增量同步响应。客户端保存 SyncToken，下次同步时作为 since 传入；
+// HasMore 为 true 时应立即使用新的令牌继续同步
+type SyncResponse struct {
+	Created   []TodoResponse  `json:"created"`
+	Updated   []TodoResponse  `json:"updated"`
+	Deleted   []SyncTombstone `json:"deleted"`
+	SyncToken string          `json:"sync_token"`
+	HasMore   bool            `json:"has_more"`
+	Full      bool            `json:"full"` // 是否为全量同步，全量同步的事项都在 created 中
+}
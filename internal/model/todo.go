@@ -1,44 +1,94 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Todo struct {
-	ID          int64      `json:"id" gorm:"primary_key"`
-	UserID      int64      `json:"-" gorm:"not null;index"`
-	Title       string     `json:"title" gorm:"not null;size:255"`
-	Content     string     `json:"content" gorm:"type:text"`
-	Status      int        `json:"status" gorm:"default:0;index"` // 0-待办，1-已完成
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	Deadline    *time.Time `json:"deadline" gorm:"index"`
-	CompletedAt *time.Time `json:"completed_at"`
+	ID          int64          `json:"id" gorm:"primary_key"`
+	UserID      int64          `json:"-" gorm:"not null;index"`
+	ProjectID   *int64         `json:"project_id" gorm:"index"` // 所属清单，为空表示未分组
+	Title       string         `json:"title" gorm:"not null;size:255"`
+	Content     string         `json:"content" gorm:"type:text"`
+	Status      int            `json:"status" gorm:"default:0;index"`   // 0-待办，1-已完成
+	Priority    int            `json:"priority" gorm:"default:0;index"` // 0-无，1-低，2-中，3-高，4-紧急
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Deadline    *time.Time     `json:"deadline" gorm:"index"`
+	CompletedAt *time.Time     `json:"completed_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`                    // 软删除时间，不为空表示在回收站中
+	Version     int64          `json:"version" gorm:"not null;default:1"` // 版本号，每次修改加一，用作 ETag
+	// 导入来源中的唯一标识，重复导入时用于去重
+	ExternalID string `json:"-" gorm:"size:255;index"`
+	// 为 true 时检查项全部完成会自动完成事项，重新打开任一检查项会重新打开事项
+	ChecklistAutoComplete bool             `json:"checklist_auto_complete" gorm:"default:false"`
+	ChecklistItems        []ChecklistItem  `json:"-" gorm:"foreignKey:TodoID"`
+	BlockedBy             []TodoDependency `json:"-" gorm:"foreignKey:TodoID"`      // 阻塞当前事项的前置事项
+	Blocking              []TodoDependency `json:"-" gorm:"foreignKey:BlockedByID"` // 被当前事项阻塞的后续事项
+}
+
+// 优先级
+const (
+	PriorityNone   = 0
+	PriorityLow    = 1
+	PriorityMedium = 2
+	PriorityHigh   = 3
+	PriorityUrgent = 4
+)
+
+// PriorityNames 优先级名称
+var PriorityNames = map[string]int{
+	"none":   PriorityNone,
+	"low":    PriorityLow,
+	"medium": PriorityMedium,
+	"high":   PriorityHigh,
+	"urgent": PriorityUrgent,
 }
 
 // CreateTodoRequest 创建待办事项请求
 type CreateTodoRequest struct {
-	Title    string `json:"title" binding:"required,min=1,max=255"`
-	Content  string `json:"content" binding:"max=1000"`
-	Deadline string `json:"deadline"` // ISO 8601 格式
+	Title     string `json:"title" binding:"required,min=1,max=255"`
+	Content   string `json:"content" binding:"max=1000"`
+	Deadline  string `json:"deadline"`   // ISO 8601 格式
+	ProjectID *int64 `json:"project_id"` // 所属清单
+	Priority  int    `json:"priority" binding:"oneof=0 1 2 3 4"`
+	// 检查项全部完成时自动完成
+	ChecklistAutoComplete bool `json:"checklist_auto_complete"`
 }
 
 // UpdateTodoRequest 更新待办事项请求
 type UpdateTodoRequest struct {
-	Title    *string `json:"title" binding:"omitempty,min=1,max=255"`
-	Content  *string `json:"content" binding:"omitempty,max=1000"`
-	Deadline *string `json:"deadline"` // ISO 8601 格式
-	Status   *int    `json:"status" binding:"omitempty,oneof=0 1"`
+	Title     *string `json:"title" binding:"omitempty,min=1,max=255"`
+	Content   *string `json:"content" binding:"omitempty,max=1000"`
+	Deadline  *string `json:"deadline"` // ISO 8601 格式
+	Status    *int    `json:"status" binding:"omitempty,oneof=0 1"`
+	ProjectID *int64  `json:"project_id"` // 传 0 表示移出清单
+	Priority  *int    `json:"priority" binding:"omitempty,oneof=0 1 2 3 4"`
+	// 检查项全部完成时自动完成
+	ChecklistAutoComplete *bool `json:"checklist_auto_complete"`
 }
 
 // TodoResponse 单个待办事项响应
 type TodoResponse struct {
-	ID          int64  `json:"id"`
-	Title       string `json:"title"`
-	Content     string `json:"content"`
-	Status      int    `json:"status"`
-	CreatedAt   int64  `json:"created_at"`   // Unix 时间戳
-	UpdatedAt   int64  `json:"updated_at"`   // Unix 时间戳
-	Deadline    *int64 `json:"deadline"`     // Unix 时间戳
-	CompletedAt *int64 `json:"completed_at"` // Unix 时间戳
+	ID          int64   `json:"id"`
+	Title       string  `json:"title"`
+	Content     string  `json:"content"`
+	Status      int     `json:"status"`
+	Priority    int     `json:"priority"`
+	ProjectID   *int64  `json:"project_id"`
+	CreatedAt   int64   `json:"created_at"`           // Unix 时间戳
+	UpdatedAt   int64   `json:"updated_at"`           // Unix 时间戳
+	Deadline    *int64  `json:"deadline"`             // Unix 时间戳
+	CompletedAt *int64  `json:"completed_at"`         // Unix 时间戳
+	Progress    string  `json:"progress"`             // 检查项完成进度，例如 3/5
+	BlockedBy   []int64 `json:"blocked_by"`           // 前置事项ID
+	Blocking    []int64 `json:"blocking"`             // 后续事项ID
+	DeletedAt   *int64  `json:"deleted_at,omitempty"` // 移入回收站时间，Unix 时间戳
+	Version     int64   `json:"version"`              // 版本号，与 ETag 对应
+	// 检查项全部完成时自动完成
+	ChecklistAutoComplete bool `json:"checklist_auto_complete"`
 }
 
 // TodoListResponse 待办事项列表响应
@@ -56,11 +106,16 @@ type TodoStats struct {
 	Pending        int64   `json:"pending"`
 	Completed      int64   `json:"completed"`
 	CompletionRate float64 `json:"completion_rate"`
+	Overdue        int64   `json:"overdue"`   // 已过截止时间的待办事项
+	DueToday       int64   `json:"due_today"` // 今天（用户时区）截止的待办事项，包括今天已过截止时间的
 }
 
 // BatchOperationResult 批量操作结果
 type BatchOperationResult struct {
-	AffectedCount int64 `json:"affected_count"`
+	AffectedCount int64  `json:"affected_count"`
+	UndoToken     string `json:"undo_token,omitempty"`      // 撤销令牌，在有效期内可用于撤销本次操作
+	UndoExpiresAt *int64 `json:"undo_expires_at,omitempty"` // 撤销令牌过期时间，Unix 时间戳
+	BlockedCount  int64  `json:"blocked_count,omitempty"`   // 因存在未完成的前置事项而没有完成的数量
 }
 
 // TodoQueryParams 查询参数
@@ -69,6 +124,21 @@ type TodoQueryParams struct {
 	Page      int    `query:"page"`       // 页码，从1开始
 	PageSize  int    `query:"page_size"`  // 每页条数
 	Keyword   string `query:"keyword"`    // 搜索关键词
-	SortBy    string `query:"sort_by"`    // 排序字段
+	SortBy    string `query:"sort_by"`    // 排序字段，urgency 表示按紧急度排序
 	SortOrder string `query:"sort_order"` // 排序方式: asc, desc
+	ProjectID int64  `query:"project_id"` // 按清单过滤
+	Priority  string `query:"priority"`   // 按优先级过滤，逗号分隔：none, low, medium, high, urgent 或 0-4
+	Blocked   string `query:"blocked"`    // true-仅被未完成前置事项阻塞的事项，false-仅未被阻塞的事项
+}
+
+// TrashQueryParams 回收站查询参数
+type TrashQueryParams struct {
+	Page     int `query:"page"`      // 页码，从1开始
+	PageSize int `query:"page_size"` // 每页条数
+}
+
+// BatchScopeParams 批量操作范围参数
+type BatchScopeParams struct {
+	ProjectID int64 `query:"project_id"` // 仅作用于指定清单，为空表示全部
+	Force     bool  `query:"force"`      // 批量完成时一并完成存在未完成前置事项的事项
 }
@@ -0,0 +1,52 @@
+package model
+
+// 导入导出格式
+const (
+	TransferFormatJSON     = "json"
+	TransferFormatCSV      = "csv"
+	TransferFormatICS      = "ics" // 仅支持导入
+	TransferFormatMarkdown = "markdown"
+)
+
+// TodoExportRecord 导入导出的单条待办事项，时间均为 ISO 8601 格式
+type TodoExportRecord struct {
+	UID         string `json:"uid"` // 唯一标识，重复导入时用于去重
+	Title       string `json:"title"`
+	Content     string `json:"content"`
+	Status      string `json:"status"`   // pending, completed
+	Priority    int    `json:"priority"` // 0-4
+	Project     string `json:"project"`  // 清单名称，导入时不存在会自动创建
+	Deadline    string `json:"deadline"`
+	CompletedAt string `json:"completed_at"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// ExportQueryParams 导出查询参数
+type ExportQueryParams struct {
+	Format string `query:"format"` // json, csv, markdown，默认 json
+}
+
+// ImportQueryParams 导入查询参数
+type ImportQueryParams struct {
+	Format string `query:"format"`  // json, csv, ics, markdown 或第三方来源（todoist, mstodo），默认按文件类型判断，无法判断时为 json
+	DryRun bool   `query:"dry_run"` // 为 true 时只校验不写入
+}
+
+// ImportError 导入校验错误
+type ImportError struct {
+	Line  int    `json:"line"` // 所在行号，从1开始
+	Field string `json:"field,omitempty"`
+	Msg   string `json:"msg"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	DryRun          bool          `json:"dry_run"`
+	Total           int           `json:"total"`              // 读取到的记录数
+	Created         int           `json:"created"`            // 新建（或试运行时将新建）的事项数
+	Duplicates      int           `json:"duplicates"`         // 已存在而跳过的事项数
+	ProjectsCreated int           `json:"projects_created"`   // 新建的清单数
+	Errors          []ImportError `json:"errors"`             // 存在错误时不会写入任何数据
+	Skipped         []ImportError `json:"skipped,omitempty"`  // ICS 和第三方来源中被跳过的记录及原因
+	Warnings        []ImportError `json:"warnings,omitempty"` // 已导入但有信息丢失的记录
+}
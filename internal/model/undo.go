@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// BatchUndo 批量操作撤销记录，保存受影响事项在操作前的状态
+type BatchUndo struct {
+	ID        int64      `json:"id" gorm:"primary_key"`
+	UserID    int64      `json:"-" gorm:"not null;index"`
+	Token     string     `json:"token" gorm:"not null;size:64;uniqueIndex"`
+	Source    string     `json:"source" gorm:"size:64"`  // 对应的批量操作，例如 batch.clear_completed
+	Snapshot  string     `json:"-" gorm:"type:longtext"` // 操作前的事项状态，JSON 格式
+	ExpiresAt time.Time  `json:"expires_at" gorm:"index"`
+	UndoneAt  *time.Time `json:"undone_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TodoSnapshot 批量操作前的事项状态
+type TodoSnapshot struct {
+	ID                    int64      `json:"id"`
+	Deleted               bool       `json:"deleted"` // 该操作是否将事项移入回收站
+	Status                int        `json:"status"`
+	CompletedAt           *time.Time `json:"completed_at"`
+	Deadline              *time.Time `json:"deadline"`
+	ProjectID             *int64     `json:"project_id"`
+	Priority              int        `json:"priority"`
+	ChecklistAutoComplete bool       `json:"checklist_auto_complete"`
+}
+
+// BatchUndoRequest 撤销批量操作请求
+type BatchUndoRequest struct {
+	Token string `json:"token"`
+}
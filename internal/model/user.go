@@ -7,6 +7,8 @@ type User struct {
 	Username     string    `json:"username" gorm:"unique;not null;size:50"`
 	Email        string    `json:"email" gorm:"unique;not null;size:50"`
 	PasswordHash string    `json:"-" gorm:"not null;size:255"`
+	TimeZone     string    `json:"time_zone" gorm:"size:64"`    // IANA 时区，为空表示 UTC，统计按该时区划分日期
+	SyncSeq      int64     `json:"-" gorm:"not null;default:0"` // 最新的同步序号，每记录一条动态加一
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -41,6 +43,7 @@ type UserInfo struct {
 	ID        int64  `json:"id"`
 	Username  string `json:"username"`
 	Email     string `json:"email"`
+	TimeZone  string `json:"time_zone"`
 	CreatedAt int64  `json:"created_at"`
 }
 
@@ -48,6 +51,7 @@ type UserInfo struct {
 type UpdateUserRequest struct {
 	Username *string `json:"username" binding:"omitempty,min=3,max=50"`
 	Email    *string `json:"email" binding:"omitempty,email"`
+	TimeZone *string `json:"time_zone" binding:"omitempty,max=64"` // IANA 时区，如 Asia/Shanghai，空字符串表示 UTC
 }
 
 // BaseResponse 基础响应结构
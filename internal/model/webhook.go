@@ -0,0 +1,179 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook 事件类型。事项事件与动态类型一一对应，批量事件与批量操作的来源一致
+const (
+	WebhookEventTodoCreated   = "todo.created"
+	WebhookEventTodoUpdated   = "todo.updated"
+	WebhookEventTodoCompleted = "todo.completed"
+	WebhookEventTodoReopened  = "todo.reopened"
+	WebhookEventTodoDeleted   = "todo.deleted"
+	WebhookEventTodoRestored  = "todo.restored"
+	WebhookEventTodoPurged    = "todo.purged"
+	WebhookEventPing          = "ping" // 测试事件，只发送给指定的 Webhook
+)
+
+// WebhookEvents 可订阅的事件，另外支持 todo.*、batch.* 和 * 通配
+var WebhookEvents = []string{
+	WebhookEventTodoCreated,
+	WebhookEventTodoUpdated,
+	WebhookEventTodoCompleted,
+	WebhookEventTodoReopened,
+	WebhookEventTodoDeleted,
+	WebhookEventTodoRestored,
+	WebhookEventTodoPurged,
+	"batch.complete",
+	"batch.reopen",
+	"batch.delete",
+	"batch.set_deadline",
+	"batch.set_fields",
+	"batch.pending",
+	"batch.clear_completed",
+	"batch.clear_pending",
+	"batch.undo",
+	"batch.operations",
+}
+
+// 投递状态
+const (
+	WebhookDeliveryPending = "pending" // 等待发送或重试
+	WebhookDeliverySuccess = "success" // 对方返回 2xx
+	WebhookDeliveryFailed  = "failed"  // 重试次数用完或 Webhook 已停用
+)
+
+// Webhook 用户注册的事件回调地址
+type Webhook struct {
+	ID          int64     `json:"id" gorm:"primary_key"`
+	UserID      int64     `json:"-" gorm:"not null;index"`
+	URL         string    `json:"url" gorm:"not null;size:2048"`
+	Events      string    `json:"events" gorm:"not null;size:1024"` // 订阅的事件，逗号分隔
+	Description string    `json:"description" gorm:"size:255"`
+	Secret      string    `json:"-" gorm:"not null;size:64"` // 签名密钥，只在创建和重置时返回
+	Active      bool      `json:"active" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery 一次事件投递，同一事件发送给多个 Webhook 时各有一条记录
+type WebhookDelivery struct {
+	ID             int64      `json:"id" gorm:"primary_key"`
+	WebhookID      int64      `json:"webhook_id" gorm:"not null;index"`
+	UserID         int64      `json:"-" gorm:"not null;index"`
+	EventID        string     `json:"event_id" gorm:"not null;size:64;index"` // 事件标识，重新投递时不变，接收方可用于去重
+	Event          string     `json:"event" gorm:"not null;size:64"`
+	Payload        string     `json:"-" gorm:"type:text"`                                    // 请求体
+	Status         string     `json:"status" gorm:"not null;size:16;index:idx_delivery_due"` // pending, success, failed
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"index:idx_delivery_due"`
+	ResponseStatus int        `json:"response_status"`                // 最近一次请求的响应状态码，0 表示请求未完成
+	ResponseBody   string     `json:"response_body" gorm:"type:text"` // 最近一次响应的开头部分
+	Error          string     `json:"error" gorm:"size:512"`          // 最近一次失败的原因
+	Duration       int64      `json:"duration"`                       // 最近一次请求耗时，毫秒
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"index"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// WebhookPayload 投递的请求体
+type WebhookPayload struct {
+	ID        string      `json:"id"` // 事件标识
+	Event     string      `json:"event"`
+	CreatedAt int64       `json:"created_at"` // Unix 时间戳
+	Data      interface{} `json:"data"`
+}
+
+// WebhookTodo 事件中的事项
+type WebhookTodo struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Content     string `json:"content"`
+	Status      int    `json:"status"`
+	Priority    int    `json:"priority"`
+	ProjectID   *int64 `json:"project_id"`
+	Deadline    *int64 `json:"deadline"`     // Unix 时间戳
+	CompletedAt *int64 `json:"completed_at"` // Unix 时间戳
+	CreatedAt   int64  `json:"created_at"`   // Unix 时间戳
+	UpdatedAt   int64  `json:"updated_at"`   // Unix 时间戳
+}
+
+// WebhookTodoEvent 事项事件的数据
+type WebhookTodoEvent struct {
+	Todo    WebhookTodo   `json:"todo"`
+	ActorID int64         `json:"actor_id"` // 0 表示系统任务
+	Source  string        `json:"source"`   // 触发来源，例如 batch.complete，为空表示单条操作
+	Changes []FieldChange `json:"changes"`
+}
+
+// WebhookBatchEvent 批量事件的数据，其中的事项另有逐条的事项事件
+type WebhookBatchEvent struct {
+	AffectedCount int64   `json:"affected_count"`
+	TodoIDs       []int64 `json:"todo_ids"`
+	UndoToken     string  `json:"undo_token,omitempty"`
+}
+
+// CreateWebhookRequest 创建 Webhook 请求
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required"`
+	Description string   `json:"description"`
+	Active      *bool    `json:"active"` // 默认启用
+}
+
+// UpdateWebhookRequest 更新 Webhook 请求，未传的字段保持不变
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url"`
+	Events      []string `json:"events"`
+	Description *string  `json:"description"`
+	Active      *bool    `json:"active"`
+}
+
+// WebhookResponse Webhook 响应
+type WebhookResponse struct {
+	ID          int64    `json:"id"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+	Active      bool     `json:"active"`
+	Secret      string   `json:"secret,omitempty"` // 仅创建和重置密钥时返回
+	CreatedAt   int64    `json:"created_at"`       // Unix 时间戳
+	UpdatedAt   int64    `json:"updated_at"`       // Unix 时间戳
+}
+
+// WebhookDeliveryResponse 投递记录响应
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *int64          `json:"next_attempt_at"` // 等待重试时的下次发送时间，Unix 时间戳
+	ResponseStatus int             `json:"response_status"`
+	ResponseBody   string          `json:"response_body"`
+	Error          string          `json:"error"`
+	Duration       int64           `json:"duration"`     // 毫秒
+	DeliveredAt    *int64          `json:"delivered_at"` // Unix 时间戳
+	CreatedAt      int64           `json:"created_at"`   // Unix 时间戳
+	Payload        json.RawMessage `json:"payload,omitempty"`
+}
+
+// WebhookDeliveryListResponse 投递记录列表响应
+type WebhookDeliveryListResponse struct {
+	Items      []WebhookDeliveryResponse `json:"items"`
+	Total      int64                     `json:"total"`
+	Page       int                       `json:"page"`
+	PageSize   int                       `json:"page_size"`
+	TotalPages int                       `json:"total_pages"`
+}
+
+// WebhookDeliveryQueryParams 投递记录查询参数
+type WebhookDeliveryQueryParams struct {
+	Page     int    `query:"page"`      // 页码，从1开始
+	PageSize int    `query:"page_size"` // 每页条数
+	Status   string `query:"status"`    // pending, success, failed
+	Event    string `query:"event"`     // 按事件类型过滤
+}
@@ -0,0 +1,132 @@
+// Package realtime 提供实时推送所需的变更通知和 WebSocket 协议实现。
+// 通知只携带用户ID，连接收到通知后自行查询新的变更，因此通知被合并或丢失时不会丢失变更
+package realtime
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Broker 在进程内和实例之间通知用户的数据发生了变化
+type Broker struct {
+	mu   sync.Mutex
+	subs map[int64]map[chan struct{}]struct{}
+
+	client   *redis.Client // 为 nil 时只通知本进程的连接
+	channel  string
+	instance string // 本实例的标识，用于忽略自己发布的消息
+}
+
+// NewBroker 创建只在进程内通知的 Broker，适用于单实例部署
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[int64]map[chan struct{}]struct{})}
+}
+
+// NewRedisBroker 创建通过 Redis 发布/订阅在多个实例之间转发通知的 Broker，需要调用 Run 接收其他实例的通知
+func NewRedisBroker(client *redis.Client, channel string) *Broker {
+	b := NewBroker()
+	b.client = client
+	b.channel = channel
+	id := make([]byte, 8)
+	rand.Read(id)
+	b.instance = hex.EncodeToString(id)
+	return b
+}
+
+// Subscribe 订阅用户的变更通知，返回的通道在有变更时可读，多次变更可能合并为一次通知。
+// 使用完毕后必须调用返回的函数取消订阅
+func (b *Broker) Subscribe(userID int64) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[chan struct{}]struct{})
+	}
+	b.subs[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subs[userID], ch)
+		if len(b.subs[userID]) == 0 {
+			delete(b.subs, userID)
+		}
+		b.mu.Unlock()
+	}
+}
+
+// Publish 通知用户的数据发生了变化，应在事务提交之后调用。b 为 nil 时不做任何事
+func (b *Broker) Publish(userID int64) {
+	if b == nil {
+		return
+	}
+	b.notify(userID)
+
+	if b.client != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		msg := b.instance + ":" + strconv.FormatInt(userID, 10)
+		if err := b.client.Publish(ctx, b.channel, msg).Err(); err != nil {
+			log.Printf("Failed to publish change notification: %v", err)
+		}
+	}
+}
+
+// Connections 当前订阅的连接数
+func (b *Broker) Connections() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	count := 0
+	for _, subs := range b.subs {
+		count += len(subs)
+	}
+	return count
+}
+
+// notify 通知本进程中订阅了该用户的连接
+func (b *Broker) notify(userID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[userID] {
+		select {
+		case ch <- struct{}{}:
+		default: // 已有未处理的通知
+		}
+	}
+}
+
+// Run 接收其他实例发布的通知，直到 ctx 结束。只在进程内通知时立即返回
+func (b *Broker) Run(ctx context.Context) {
+	if b.client == nil {
+		return
+	}
+	sub := b.client.Subscribe(ctx, b.channel)
+	defer sub.Close()
+
+	// 连接断开时 go-redis 会自动重新订阅
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			instance, id, found := strings.Cut(msg.Payload, ":")
+			if !found || instance == b.instance {
+				continue
+			}
+			if userID, err := strconv.ParseInt(id, 10, 64); err == nil {
+				b.notify(userID)
+			}
+		}
+	}
+}
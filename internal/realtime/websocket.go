@@ -0,0 +1,263 @@
+package realtime
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket 操作码（RFC 6455 5.2）
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// WebSocket 关闭状态码（RFC 6455 7.4.1）
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseMessageTooBig   = 1009
+	CloseInternalError   = 1011
+	closeNoStatusPresent = 1005
+)
+
+// MaxMessageSize 接收的单条消息最大长度，客户端只需要发送很小的控制消息
+const MaxMessageSize = 64 << 10
+
+// websocketGUID 计算 Sec-WebSocket-Accept 使用的固定值
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var (
+	// ErrMessageTooBig 消息超过 MaxMessageSize
+	ErrMessageTooBig = errors.New("websocket: message too big")
+	// ErrProtocol 对方违反了协议，例如未掩码的客户端帧
+	ErrProtocol = errors.New("websocket: protocol error")
+)
+
+// CloseError 对方发送的关闭帧
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return "websocket: closed by peer"
+}
+
+// IsUpgradeRequest 判断请求头是否为 WebSocket 握手
+func IsUpgradeRequest(upgrade, connection, version, key string) bool {
+	if !strings.EqualFold(strings.TrimSpace(upgrade), "websocket") || key == "" || strings.TrimSpace(version) != "13" {
+		return false
+	}
+	for _, token := range strings.Split(connection, ",") {
+		if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptKey 根据客户端的 Sec-WebSocket-Key 计算 Sec-WebSocket-Accept
+func AcceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(strings.TrimSpace(key)))
+	h.Write([]byte(websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Conn 服务端的 WebSocket 连接。读取只能在一个协程中进行，写入可以并发
+type Conn struct {
+	conn net.Conn
+	r    *bufio.Reader
+
+	writeMu sync.Mutex
+	closed  bool
+}
+
+// NewConn 在完成握手的连接上创建 WebSocket 连接
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{conn: conn, r: bufio.NewReader(conn)}
+}
+
+// SetReadDeadline 设置读取超时，用于检测对方不再响应心跳
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// ReadMessage 读取一条完整的数据消息。收到 ping 时自动回复 pong，
+// 收到 pong 时返回 OpPong 以便调用方更新超时；收到关闭帧时回复关闭帧并返回 *CloseError
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var (
+		opcode  int
+		message []byte
+	)
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case OpPing:
+			if err := c.WriteControl(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			return OpPong, payload, nil
+		case OpClose:
+			closeErr := &CloseError{Code: closeNoStatusPresent}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			c.WriteClose(closeErr.Code, "")
+			return 0, nil, closeErr
+		case OpContinuation:
+			if opcode == 0 {
+				return 0, nil, ErrProtocol
+			}
+		case OpText, OpBinary:
+			if opcode != 0 {
+				return 0, nil, ErrProtocol
+			}
+			opcode = op
+		default:
+			return 0, nil, ErrProtocol
+		}
+
+		if len(message)+len(payload) > MaxMessageSize {
+			return 0, nil, ErrMessageTooBig
+		}
+		message = append(message, payload...)
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// readFrame 读取一帧并去掉掩码
+func (c *Conn) readFrame() (bool, int, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, ErrProtocol // 未协商扩展，保留位必须为0
+	}
+	opcode := int(header[0] & 0x0F)
+	masked := header[1]&0x80 != 0
+	if !masked {
+		return false, 0, nil, ErrProtocol // 客户端发送的帧必须掩码
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= OpClose && (length > 125 || !fin) {
+		return false, 0, nil, ErrProtocol // 控制帧不能分片且不超过125字节
+	}
+	if length > MaxMessageSize {
+		return false, 0, nil, ErrMessageTooBig
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteText 发送文本消息
+func (c *Conn) WriteText(data []byte) error {
+	return c.writeFrame(OpText, data)
+}
+
+// WriteControl 发送 ping 或 pong
+func (c *Conn) WriteControl(opcode int, data []byte) error {
+	if len(data) > 125 {
+		data = data[:125]
+	}
+	return c.writeFrame(opcode, data)
+}
+
+// WriteClose 发送关闭帧，之后不能再发送任何消息
+func (c *Conn) WriteClose(code int, reason string) error {
+	var payload []byte
+	if code != closeNoStatusPresent {
+		payload = make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		payload = append(payload, reason...)
+		if len(payload) > 125 {
+			payload = payload[:125]
+		}
+	}
+	err := c.writeFrame(OpClose, payload)
+	c.writeMu.Lock()
+	c.closed = true
+	c.writeMu.Unlock()
+	return err
+}
+
+// writeFrame 发送不分片、不掩码的一帧
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+
+	header := make([]byte, 2, 10+len(payload))
+	header[0] = 0x80 | byte(opcode)
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	_, err := c.conn.Write(append(header, payload...))
+	return err
+}
+
+// Close 关闭底层连接
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
@@ -4,6 +4,7 @@ import (
 	"RemindGo/internal/handler"
 	"RemindGo/internal/middleware"
 
+	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
 	"github.com/hertz-contrib/jwt"
 )
@@ -11,13 +12,29 @@ import (
 func SetupRoutes(h *server.Hertz,
 	userHandler *handler.UserHandler,
 	todoHandler *handler.TodoHandler,
-	jwtMiddleware *jwt.HertzJWTMiddleware) {
+	projectHandler *handler.ProjectHandler,
+	calendarHandler *handler.CalendarHandler,
+	caldavHandler *handler.CalDAVHandler,
+	digestHandler *handler.DigestHandler,
+	webhookHandler *handler.WebhookHandler,
+	jwtMiddleware *jwt.HertzJWTMiddleware,
+	idempotencyMiddleware app.HandlerFunc) {
 
 	// 引入全局中间件
 	h.Use(middleware.CORS())
 	h.Use(middleware.Logger())
 	h.Use(middleware.Recovery())
 
+	// CalDAV 服务发现
+	h.GET("/.well-known/caldav", caldavHandler.WellKnown)
+	h.Handle("PROPFIND", "/.well-known/caldav", caldavHandler.WellKnown)
+
+	// CalDAV (使用应用密码进行 Basic 认证，不需要JWT)
+	dav := h.Group("/dav")
+	for _, method := range handler.CalDAVMethods {
+		dav.Handle(method, "/*path", caldavHandler.ServeDAV)
+	}
+
 	// API v1路由组
 	v1 := h.Group("/api/v1")
 	{
@@ -36,6 +53,11 @@ func SetupRoutes(h *server.Hertz,
 		{
 			users.GET("/profile", userHandler.GetProfile)    // 获取用户信息
 			users.PUT("/profile", userHandler.UpdateProfile) // 更新用户信息
+
+			// 应用密码 (用于 CalDAV 等客户端)
+			users.GET("/app-passwords", userHandler.GetAppPasswords)          // 获取应用密码列表
+			users.POST("/app-passwords", userHandler.CreateAppPassword)       // 创建应用密码
+			users.DELETE("/app-passwords/:id", userHandler.DeleteAppPassword) // 删除应用密码
 		}
 
 		// 待办事项相关路由 (需要JWT认证)
@@ -43,11 +65,19 @@ func SetupRoutes(h *server.Hertz,
 		todos.Use(jwtMiddleware.MiddlewareFunc())
 		{
 			// 统计信息 (必须在 /:id 之前)
-			todos.GET("/stats", todoHandler.GetStats) // 获取统计信息
+			todos.GET("/stats", todoHandler.GetStats)                      // 获取统计信息
+			todos.GET("/stats/timeseries", todoHandler.GetStatsTimeseries) // 获取时间序列统计
+
+			// 导入导出 (必须在 /:id 之前)
+			todos.GET("/export", todoHandler.ExportTodos)  // 导出待办事项
+			todos.POST("/import", todoHandler.ImportTodos) // 导入待办事项
 
-			// 批量操作 (必须在 /:id 之前)
+			// 批量操作 (必须在 /:id 之前，支持幂等键)
 			batch := todos.Group("/batch")
+			batch.Use(idempotencyMiddleware)
 			{
+				batch.POST("", todoHandler.BatchAction)                           // 按ID或过滤条件批量操作
+				batch.POST("/undo", todoHandler.UndoBatch)                        // 撤销批量操作
 				batch.PATCH("/complete", todoHandler.BatchComplete)               // 批量完成所有待办事项
 				batch.PATCH("/pending", todoHandler.BatchPending)                 // 批量重置所有已完成事项
 				batch.DELETE("/clear-completed", todoHandler.BatchClearCompleted) // 批量删除已完成事项
@@ -55,12 +85,123 @@ func SetupRoutes(h *server.Hertz,
 			}
 
 			// 基础CRUD操作
-			todos.GET("", todoHandler.GetTodoList)             // 获取待办事项列表
-			todos.POST("", todoHandler.CreateTodo)             // 创建待办事项
-			todos.GET("/:id", todoHandler.GetTodo)             // 获取单个待办事项
-			todos.PUT("/:id", todoHandler.UpdateTodo)          // 更新待办事项
-			todos.DELETE("/:id", todoHandler.DeleteTodo)       // 删除待办事项
-			todos.PATCH("/:id/toggle", todoHandler.ToggleTodo) // 切换待办事项状态
+			todos.GET("", todoHandler.GetTodoList)                        // 获取待办事项列表
+			todos.POST("", idempotencyMiddleware, todoHandler.CreateTodo) // 创建待办事项（支持幂等键）
+			todos.GET("/:id", todoHandler.GetTodo)                        // 获取单个待办事项
+			todos.PUT("/:id", todoHandler.UpdateTodo)                     // 更新待办事项
+			todos.DELETE("/:id", todoHandler.DeleteTodo)                  // 删除待办事项
+			todos.PATCH("/:id/toggle", todoHandler.ToggleTodo)            // 切换待办事项状态
+			todos.GET("/:id/activity", todoHandler.GetTodoActivity)       // 获取待办事项动态
+
+			// 检查项
+			todos.GET("/:id/checklist", todoHandler.GetChecklist)                          // 获取检查项列表
+			todos.POST("/:id/checklist", todoHandler.AddChecklistItem)                     // 添加检查项
+			todos.PATCH("/:id/checklist/reorder", todoHandler.ReorderChecklist)            // 调整检查项顺序
+			todos.PUT("/:id/checklist/:item_id", todoHandler.UpdateChecklistItem)          // 更新检查项
+			todos.DELETE("/:id/checklist/:item_id", todoHandler.DeleteChecklistItem)       // 删除检查项
+			todos.PATCH("/:id/checklist/:item_id/toggle", todoHandler.ToggleChecklistItem) // 切换检查项状态
+
+			// 内容版本
+			todos.GET("/:id/revisions", todoHandler.GetRevisions)                  // 获取版本列表
+			todos.GET("/:id/revisions/diff", todoHandler.DiffRevisions)            // 比较两个版本
+			todos.GET("/:id/revisions/:rev", todoHandler.GetRevision)              // 获取指定版本
+			todos.POST("/:id/revisions/:rev/restore", todoHandler.RestoreRevision) // 恢复到指定版本
+
+			// 前置事项
+			todos.POST("/:id/dependencies", todoHandler.AddDependency)                     // 添加前置事项
+			todos.DELETE("/:id/dependencies/:blocked_by_id", todoHandler.RemoveDependency) // 移除前置事项
+		}
+
+		// 多操作批量路由 (需要JWT认证，支持幂等键)
+		batch := v1.Group("/batch")
+		batch.Use(jwtMiddleware.MiddlewareFunc(), idempotencyMiddleware)
+		{
+			batch.POST("", todoHandler.ExecuteBatch) // 在同一事务中执行多个操作
+		}
+
+		// 动态相关路由 (需要JWT认证)
+		activity := v1.Group("/activity")
+		activity.Use(jwtMiddleware.MiddlewareFunc())
+		{
+			activity.GET("", todoHandler.GetActivityFeed) // 获取全部待办事项动态
+		}
+
+		// 增量同步 (需要JWT认证)
+		sync := v1.Group("/sync")
+		sync.Use(jwtMiddleware.MiddlewareFunc())
+		{
+			sync.GET("", todoHandler.Sync) // 获取令牌之后的变更
+		}
+
+		// 回收站相关路由 (需要JWT认证)
+		trash := v1.Group("/trash")
+		trash.Use(jwtMiddleware.MiddlewareFunc())
+		{
+			trash.GET("", todoHandler.GetTrash)                 // 获取回收站列表
+			trash.DELETE("", todoHandler.EmptyTrash)            // 清空回收站
+			trash.POST("/:id/restore", todoHandler.RestoreTodo) // 恢复待办事项
+			trash.DELETE("/:id", todoHandler.PurgeTodo)         // 永久删除待办事项
+		}
+
+		// 实时推送 (需要JWT认证，浏览器可通过 access_token 查询参数传递Token)
+		stream := v1.Group("/stream")
+		stream.Use(middleware.QueryToken(), jwtMiddleware.MiddlewareFunc())
+		{
+			stream.GET("", todoHandler.StreamChanges)             // Server-Sent Events
+			stream.GET("/ws", todoHandler.StreamChangesWebSocket) // WebSocket
+		}
+
+		// 日历订阅相关路由
+		calendar := v1.Group("/calendar")
+		{
+			// 订阅内容通过地址中的令牌鉴权 (不需要JWT认证)
+			calendar.GET("/feeds/:token", calendarHandler.ServeFeed) // 获取 iCalendar 订阅内容
+
+			// 订阅管理 (需要JWT认证)
+			calendar.GET("/feed", jwtMiddleware.MiddlewareFunc(), calendarHandler.GetFeed)       // 获取订阅地址
+			calendar.POST("/feed", jwtMiddleware.MiddlewareFunc(), calendarHandler.ResetFeed)    // 创建或重置订阅地址
+			calendar.DELETE("/feed", jwtMiddleware.MiddlewareFunc(), calendarHandler.RevokeFeed) // 撤销订阅
+		}
+
+		// 摘要邮件相关路由
+		digest := v1.Group("/digest")
+		{
+			// 退订链接通过地址中的令牌鉴权 (不需要JWT认证)
+			digest.GET("/unsubscribe/:token", digestHandler.UnsubscribePage) // 退订确认页面
+			digest.POST("/unsubscribe/:token", digestHandler.Unsubscribe)    // 退订摘要邮件，也用于一键退订
+
+			// 摘要设置 (需要JWT认证)
+			digest.GET("/settings", jwtMiddleware.MiddlewareFunc(), digestHandler.GetSetting)    // 获取摘要设置
+			digest.PUT("/settings", jwtMiddleware.MiddlewareFunc(), digestHandler.UpdateSetting) // 更新摘要设置
+			digest.GET("/preview", jwtMiddleware.MiddlewareFunc(), digestHandler.Preview)        // 预览摘要邮件
+		}
+
+		// Webhook 相关路由 (需要JWT认证)
+		webhooks := v1.Group("/webhooks")
+		webhooks.Use(jwtMiddleware.MiddlewareFunc())
+		{
+			webhooks.GET("", webhookHandler.GetWebhooks)                                      // 获取 Webhook 列表
+			webhooks.POST("", webhookHandler.CreateWebhook)                                   // 创建 Webhook
+			webhooks.GET("/:id", webhookHandler.GetWebhook)                                   // 获取单个 Webhook
+			webhooks.PUT("/:id", webhookHandler.UpdateWebhook)                                // 更新 Webhook
+			webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)                             // 删除 Webhook
+			webhooks.POST("/:id/secret", webhookHandler.RotateSecret)                         // 重置签名密钥
+			webhooks.POST("/:id/ping", webhookHandler.Ping)                                   // 发送测试事件
+			webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)                     // 获取投递记录
+			webhooks.GET("/:id/deliveries/:delivery_id", webhookHandler.GetDelivery)          // 获取单条投递记录
+			webhooks.POST("/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver) // 重新投递
+		}
+
+		// 清单相关路由 (需要JWT认证)
+		projects := v1.Group("/projects")
+		projects.Use(jwtMiddleware.MiddlewareFunc())
+		{
+			projects.GET("", projectHandler.GetProjectList)            // 获取清单列表
+			projects.POST("", projectHandler.CreateProject)            // 创建清单
+			projects.GET("/:id", projectHandler.GetProject)            // 获取单个清单
+			projects.PUT("/:id", projectHandler.UpdateProject)         // 更新清单
+			projects.DELETE("/:id", projectHandler.DeleteProject)      // 删除清单
+			projects.GET("/:id/todos", projectHandler.GetProjectTodos) // 获取清单下的待办事项
 		}
 	}
 }
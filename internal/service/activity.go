@@ -0,0 +1,171 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// GetTodoActivity 获取单个待办事项的动态（包括回收站中的事项）
+func (s *TodoService) GetTodoActivity(userID, todoID int64, params *model.ActivityQueryParams) (*model.ActivityListResponse, error) {
+	var count int64
+	if err := s.db.Unscoped().Model(&model.Todo{}).
+		Where("id = ? AND user_id = ?", todoID, userID).
+		Count(&count).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	if count == 0 {
+		return nil, errors.New("待办事项不存在")
+	}
+
+	query := s.db.Model(&model.TodoActivity{}).Where("user_id = ? AND todo_id = ?", userID, todoID)
+	return s.listActivity(query, params)
+}
+
+// GetActivityFeed 获取用户全部待办事项的动态
+func (s *TodoService) GetActivityFeed(userID int64, params *model.ActivityQueryParams) (*model.ActivityListResponse, error) {
+	query := s.db.Model(&model.TodoActivity{}).Where("user_id = ?", userID)
+	return s.listActivity(query, params)
+}
+
+// listActivity 分页查询动态，按时间倒序
+func (s *TodoService) listActivity(query *gorm.DB, params *model.ActivityQueryParams) (*model.ActivityListResponse, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 20
+	}
+	if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+	if params.Action != "" {
+		query = query.Where("action = ?", params.Action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+	var activities []model.TodoActivity
+	if err := query.Order("id desc").Offset(offset).Limit(params.PageSize).Find(&activities).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	items := make([]model.ActivityResponse, len(activities))
+	for i := range activities {
+		items[i] = activityToResponse(&activities[i])
+	}
+
+	return &model.ActivityListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(params.PageSize))),
+	}, nil
+}
+
+// recordActivity 在事务中追加一条动态，同时生成对应的 Webhook 事件
+func recordActivity(tx *gorm.DB, actorID int64, todo *model.Todo, action, source string, changes []model.FieldChange) error {
+	activity := model.TodoActivity{
+		UserID:  todo.UserID,
+		ActorID: actorID,
+		TodoID:  todo.ID,
+		Action:  action,
+		Source:  source,
+	}
+	if len(changes) > 0 {
+		data, err := json.Marshal(changes)
+		if err != nil {
+			return errors.New("记录动态失败")
+		}
+		activity.Changes = string(data)
+	}
+
+	seq, err := nextSyncSeq(tx, todo.UserID)
+	if err != nil {
+		return err
+	}
+	activity.Seq = seq
+
+	if err := tx.Create(&activity).Error; err != nil {
+		return errors.New("记录动态失败")
+	}
+	return enqueueTodoEvent(tx, actorID, todo, action, source, changes)
+}
+
+// updateAction 根据状态变化确定动态类型
+func updateAction(before, after *model.Todo) string {
+	if before.Status != after.Status {
+		if after.Status == 1 {
+			return model.ActivityCompleted
+		}
+		return model.ActivityReopened
+	}
+	return model.ActivityUpdated
+}
+
+// diffTodo 比较事项变更前后的字段，before 为空表示新建
+func diffTodo(before, after *model.Todo) []model.FieldChange {
+	if before == nil {
+		before = &model.Todo{}
+	}
+
+	var changes []model.FieldChange
+	add := func(field string, oldValue, newValue interface{}) {
+		if oldValue != newValue {
+			changes = append(changes, model.FieldChange{Field: field, Before: oldValue, After: newValue})
+		}
+	}
+
+	add("title", before.Title, after.Title)
+	add("content", before.Content, after.Content)
+	add("status", before.Status, after.Status)
+	add("priority", before.Priority, after.Priority)
+	add("project_id", int64Value(before.ProjectID), int64Value(after.ProjectID))
+	add("deadline", timeValue(before.Deadline), timeValue(after.Deadline))
+	add("completed_at", timeValue(before.CompletedAt), timeValue(after.CompletedAt))
+	add("checklist_auto_complete", before.ChecklistAutoComplete, after.ChecklistAutoComplete)
+	return changes
+}
+
+// int64Value 将可空整数转换为可比较的值
+func int64Value(value *int64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// timeValue 将可空时间转换为 ISO 8601 字符串
+func timeValue(value *time.Time) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+// activityToResponse 将动态模型转换为响应格式
+func activityToResponse(activity *model.TodoActivity) model.ActivityResponse {
+	resp := model.ActivityResponse{
+		ID:        activity.ID,
+		TodoID:    activity.TodoID,
+		ActorID:   activity.ActorID,
+		Action:    activity.Action,
+		Source:    activity.Source,
+		Changes:   []model.FieldChange{},
+		CreatedAt: activity.CreatedAt.Unix(),
+	}
+	if activity.Changes != "" {
+		json.Unmarshal([]byte(activity.Changes), &resp.Changes)
+	}
+	return resp
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MaxAppPasswordsPerUser 每个用户最多可创建的应用密码数量
+var MaxAppPasswordsPerUser = 20
+
+// GetAppPasswords 获取应用密码列表
+func (s *UserService) GetAppPasswords(userID int64) ([]model.AppPassword, error) {
+	var passwords []model.AppPassword
+	if err := s.db.Where("user_id = ?", userID).Order("id asc").Find(&passwords).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	return passwords, nil
+}
+
+// CreateAppPassword 创建应用密码，返回记录和只展示一次的明文密码
+func (s *UserService) CreateAppPassword(userID int64, req *model.CreateAppPasswordRequest) (*model.AppPassword, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return nil, "", errors.New("名称长度应为1-100个字符")
+	}
+
+	var count int64
+	if err := s.db.Model(&model.AppPassword{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, "", errors.New("创建失败")
+	}
+	if count >= int64(MaxAppPasswordsPerUser) {
+		return nil, "", errors.New("应用密码数量已达上限")
+	}
+
+	password, err := randomToken(16)
+	if err != nil {
+		return nil, "", errors.New("创建失败")
+	}
+	appPassword := model.AppPassword{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashAppPassword(password),
+	}
+	if err := s.db.Create(&appPassword).Error; err != nil {
+		return nil, "", errors.New("创建失败")
+	}
+	return &appPassword, password, nil
+}
+
+// DeleteAppPassword 删除应用密码，使用该密码的客户端随即失去访问权限
+func (s *UserService) DeleteAppPassword(userID, passwordID int64) error {
+	result := s.db.Where("id = ? AND user_id = ?", passwordID, userID).Delete(&model.AppPassword{})
+	if result.Error != nil {
+		return errors.New("删除失败")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("应用密码不存在")
+	}
+	return nil
+}
+
+// AuthenticateAppPassword 使用用户名（或邮箱）和应用密码认证
+func (s *UserService) AuthenticateAppPassword(username, password string) (*model.User, error) {
+	var appPassword model.AppPassword
+	if err := s.db.Where("token_hash = ?", hashAppPassword(password)).First(&appPassword).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("用户名或应用密码错误")
+		}
+		return nil, errors.New("认证失败")
+	}
+
+	var user model.User
+	if err := s.db.First(&user, appPassword.UserID).Error; err != nil {
+		return nil, errors.New("用户名或应用密码错误")
+	}
+	if user.Username != username && user.Email != username {
+		return nil, errors.New("用户名或应用密码错误")
+	}
+
+	// 最近使用时间精确到分钟即可，避免每个请求都写库
+	now := time.Now()
+	if appPassword.LastUsedAt == nil || now.Sub(*appPassword.LastUsedAt) > time.Minute {
+		s.db.Model(&appPassword).Update("last_used_at", &now)
+	}
+	return &user, nil
+}
+
+// hashAppPassword 应用密码是高熵随机串，使用 SHA-256 即可安全存储并支持按索引查找
+func hashAppPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 
 	"RemindGo/internal/model"
@@ -19,7 +21,7 @@ type AuthService struct {
 func NewAuthService(db *gorm.DB) *AuthService {
 	return &AuthService{
 		db:          db,
-		userService: NewUserService(db),
+		userService: NewUserService(db, nil),
 	}
 }
 
@@ -122,3 +124,12 @@ func (s *AuthService) IsEmailAvailable(email string) (bool, error) {
 	}
 	return count == 0, nil
 }
+
+// randomToken 生成 n 字节随机数的十六进制令牌
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
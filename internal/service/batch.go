@@ -0,0 +1,373 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MaxBatchIDs 单次选择性批量操作最多可指定的事项数量
+var MaxBatchIDs = 500
+
+// BatchAction 对指定ID或符合过滤条件的事项执行批量操作，整体在一个事务中完成。
+// 不存在或不属于当前用户的ID不会导致失败，而是在结果中标记为 not_found
+func (s *TodoService) BatchAction(userID int64, req *model.BatchActionRequest) (*model.BatchActionResponse, error) {
+	defer s.changed(userID)
+
+	if len(req.IDs) == 0 && req.Filter == nil {
+		return nil, errors.New("需要指定 ids 或 filter")
+	}
+	if len(req.IDs) > 0 && req.Filter != nil {
+		return nil, errors.New("ids 与 filter 只能指定一个")
+	}
+	if len(req.IDs) > MaxBatchIDs {
+		return nil, errors.New("指定的事项数量超过上限")
+	}
+
+	updates, err := s.batchActionUpdates(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.BatchActionResponse{
+		Action:  req.Action,
+		Results: []model.BatchItemResult{},
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if req.Action == model.BatchActionComplete {
+			if err := lockDependencies(tx, userID); err != nil {
+				return err
+			}
+		}
+		query := tx.Model(&model.Todo{}).Where("user_id = ?", userID)
+		if len(req.IDs) > 0 {
+			query = query.Where("id IN ?", req.IDs)
+		} else {
+			var err error
+			if query, err = s.applyFilter(query, req.Filter); err != nil {
+				return err
+			}
+		}
+		todos, err := findTodos(query.Order("id asc"))
+		if err != nil {
+			return errors.New("批量操作失败")
+		}
+
+		// 已处于目标状态的事项跳过
+		var targets []model.Todo
+		statuses := make(map[int64]string)
+		for _, todo := range todos {
+			if (req.Action == model.BatchActionComplete && todo.Status == 1) ||
+				(req.Action == model.BatchActionReopen && todo.Status == 0) {
+				statuses[todo.ID] = model.BatchItemSkipped
+				continue
+			}
+			targets = append(targets, todo)
+		}
+
+		// 存在未完成前置事项的事项不完成，除非指定 force
+		if req.Action == model.BatchActionComplete && !req.Force {
+			blocked, err := pendingBlockedIDs(tx, todoIDs(targets))
+			if err != nil {
+				return err
+			}
+			for id := range blocked {
+				statuses[id] = model.BatchItemBlocked
+			}
+			targets = excludeTodos(targets, blocked)
+		}
+
+		source := "batch." + req.Action
+		deleted := req.Action == model.BatchActionDelete
+		var affected int64
+		if deleted {
+			affected, err = batchSoftDelete(tx, userID, targets, source)
+		} else {
+			affected, err = batchUpdate(tx, userID, targets, updates, source)
+		}
+		if err != nil {
+			return err
+		}
+
+		result, err := batchResult(tx, userID, source, deleted, targets, affected)
+		if err != nil {
+			return err
+		}
+		resp.AffectedCount = result.AffectedCount
+		resp.UndoToken = result.UndoToken
+		resp.UndoExpiresAt = result.UndoExpiresAt
+
+		resp.Results = batchResults(req.IDs, todos, statuses)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// batchActionUpdates 校验批量操作并构建需要更新的字段，删除操作返回 nil
+func (s *TodoService) batchActionUpdates(userID int64, req *model.BatchActionRequest) (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+	switch req.Action {
+	case model.BatchActionComplete:
+		now := time.Now()
+		updates["status"] = 1
+		updates["completed_at"] = &now
+	case model.BatchActionReopen:
+		updates["status"] = 0
+		updates["completed_at"] = nil
+	case model.BatchActionDelete:
+		return nil, nil
+	case model.BatchActionSetDeadline:
+		if req.Deadline == nil {
+			return nil, errors.New("需要指定截止时间")
+		}
+		deadline, err := deadlineUpdate(*req.Deadline)
+		if err != nil {
+			return nil, err
+		}
+		updates["deadline"] = deadline
+	case model.BatchActionSetFields:
+		if req.Fields == nil {
+			return nil, errors.New("没有需要更新的字段")
+		}
+		if req.Fields.ProjectID != nil {
+			if *req.Fields.ProjectID <= 0 {
+				updates["project_id"] = nil
+			} else {
+				if err := s.checkProject(userID, *req.Fields.ProjectID); err != nil {
+					return nil, err
+				}
+				updates["project_id"] = *req.Fields.ProjectID
+			}
+		}
+		if req.Fields.Priority != nil {
+			if !validPriority(*req.Fields.Priority) {
+				return nil, errors.New("优先级无效")
+			}
+			updates["priority"] = *req.Fields.Priority
+		}
+		if req.Fields.Deadline != nil {
+			deadline, err := deadlineUpdate(*req.Fields.Deadline)
+			if err != nil {
+				return nil, err
+			}
+			updates["deadline"] = deadline
+		}
+		if req.Fields.ChecklistAutoComplete != nil {
+			updates["checklist_auto_complete"] = *req.Fields.ChecklistAutoComplete
+		}
+		if len(updates) == 0 {
+			return nil, errors.New("没有需要更新的字段")
+		}
+	default:
+		return nil, errors.New("批量操作类型无效")
+	}
+	return updates, nil
+}
+
+// batchResults 生成逐项结果。按ID操作时按请求顺序列出每个ID，按过滤条件操作时列出命中的事项
+func batchResults(ids []int64, todos []model.Todo, statuses map[int64]string) []model.BatchItemResult {
+	status := func(id int64) string {
+		if status, ok := statuses[id]; ok {
+			return status
+		}
+		return model.BatchItemOK
+	}
+
+	if len(ids) == 0 {
+		results := make([]model.BatchItemResult, len(todos))
+		for i, todo := range todos {
+			results[i] = model.BatchItemResult{ID: todo.ID, Status: status(todo.ID)}
+		}
+		return results
+	}
+
+	found := make(map[int64]bool, len(todos))
+	for _, todo := range todos {
+		found[todo.ID] = true
+	}
+	seen := make(map[int64]bool, len(ids))
+	results := make([]model.BatchItemResult, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !found[id] {
+			results = append(results, model.BatchItemResult{ID: id, Status: model.BatchItemNotFound})
+			continue
+		}
+		results = append(results, model.BatchItemResult{ID: id, Status: status(id)})
+	}
+	return results
+}
+
+// deadlineUpdate 解析截止时间更新值，空字符串表示清除截止时间
+func deadlineUpdate(value string) (interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+	deadline, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New("截止时间格式错误，请使用ISO 8601格式")
+	}
+	return &deadline, nil
+}
+
+// MaxBatchOperations 多操作批量接口单次最多包含的操作数量
+var MaxBatchOperations = 100
+
+// ExecuteBatch 按顺序在同一事务中执行多个操作，任一操作失败则全部回滚。
+// 返回的结果包含已执行的操作，失败时最后一项为失败的操作
+func (s *TodoService) ExecuteBatch(userID int64, req *model.BatchOperationsRequest) (*model.BatchOperationsResponse, error) {
+	defer s.changed(userID)
+
+	if len(req.Operations) == 0 {
+		return nil, errors.New("操作列表不能为空")
+	}
+	if len(req.Operations) > MaxBatchOperations {
+		return nil, errors.New("操作数量超过上限")
+	}
+
+	resp := &model.BatchOperationsResponse{
+		Results: make([]model.BatchOperationItemResult, 0, len(req.Operations)),
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txService := s.withDB(tx)
+		tempIDs := make(map[string]int64)
+
+		for i := range req.Operations {
+			op := &req.Operations[i]
+			result, err := txService.executeOperation(userID, op, tempIDs)
+			result.Index = i
+			result.Op = op.Op
+			result.TempID = op.TempID
+			if err != nil {
+				result.Msg = err.Error()
+				resp.Results = append(resp.Results, result)
+				return err
+			}
+			result.OK = true
+			resp.Results = append(resp.Results, result)
+		}
+
+		var ids []int64
+		for _, result := range resp.Results {
+			if result.ID != 0 {
+				ids = append(ids, result.ID)
+			}
+		}
+		return enqueueWebhookEvent(tx, userID, "batch.operations", model.WebhookBatchEvent{
+			AffectedCount: int64(len(resp.Results)),
+			TodoIDs:       ids,
+		})
+	})
+	return resp, err
+}
+
+// executeOperation 执行单个操作，create 成功后登记临时ID
+func (s *TodoService) executeOperation(userID int64, op *model.BatchOperation, tempIDs map[string]int64) (model.BatchOperationItemResult, error) {
+	var result model.BatchOperationItemResult
+
+	if op.Op == model.BatchOpCreate {
+		if op.TempID != "" {
+			if _, ok := tempIDs[op.TempID]; ok {
+				return result, errors.New("临时ID重复")
+			}
+		}
+		var req model.CreateTodoRequest
+		if err := json.Unmarshal(op.Data, &req); err != nil {
+			return result, errors.New("操作数据格式错误")
+		}
+		if err := validateTodoFields(&req.Title, &req.Content, nil, &req.Priority); err != nil {
+			return result, err
+		}
+		todo, err := s.CreateTodo(userID, &req)
+		if err != nil {
+			return result, err
+		}
+		if op.TempID != "" {
+			tempIDs[op.TempID] = todo.ID
+		}
+		// 重新查询以包含关联数据
+		todo, err = s.loadTodo(userID, todo.ID)
+		if err != nil {
+			return result, err
+		}
+		resp := s.todoToResponse(todo)
+		result.ID = todo.ID
+		result.Todo = &resp
+		return result, nil
+	}
+
+	// 其余操作需要定位目标事项
+	todoID := op.ID
+	if op.Ref != "" {
+		id, ok := tempIDs[op.Ref]
+		if !ok {
+			return result, errors.New("引用的临时ID不存在")
+		}
+		todoID = id
+	}
+	if todoID <= 0 {
+		return result, errors.New("需要指定事项ID")
+	}
+	result.ID = todoID
+
+	var todo *model.Todo
+	var err error
+	switch op.Op {
+	case model.BatchOpUpdate:
+		var req model.UpdateTodoRequest
+		if err := json.Unmarshal(op.Data, &req); err != nil {
+			return result, errors.New("操作数据格式错误")
+		}
+		if err := validateTodoFields(req.Title, req.Content, req.Status, req.Priority); err != nil {
+			return result, err
+		}
+		todo, err = s.UpdateTodo(userID, todoID, &req, op.Force, "")
+	case model.BatchOpToggle:
+		todo, err = s.ToggleTodo(userID, todoID, op.Force, "")
+	case model.BatchOpDelete:
+		return result, s.DeleteTodo(userID, todoID, "")
+	default:
+		return result, errors.New("操作类型无效")
+	}
+	if err != nil {
+		return result, err
+	}
+
+	resp := s.todoToResponse(todo)
+	result.Todo = &resp
+	return result, nil
+}
+
+// validateTodoFields 校验操作数据中的事项字段，规则与单个事项接口的请求绑定一致，nil 表示未指定
+func validateTodoFields(title, content *string, status, priority *int) error {
+	if title != nil {
+		if strings.TrimSpace(*title) == "" {
+			return errors.New("标题不能为空")
+		}
+		if utf8.RuneCountInString(*title) > 255 {
+			return errors.New("标题不能超过255个字符")
+		}
+	}
+	if content != nil && utf8.RuneCountInString(*content) > 1000 {
+		return errors.New("内容不能超过1000个字符")
+	}
+	if status != nil && *status != 0 && *status != 1 {
+		return errors.New("状态无效")
+	}
+	if priority != nil && !validPriority(*priority) {
+		return errors.New("优先级无效")
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"RemindGo/internal/ical"
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CalendarObjectName 事项在 CalDAV 日历中的资源名
+func CalendarObjectName(todo *model.Todo) string {
+	return exportUID(todo) + ".ics"
+}
+
+// CalendarObjects 获取 CalDAV 日历中的事项，pendingOnly 为 true 时只返回未完成的事项
+func (s *TodoService) CalendarObjects(userID int64, pendingOnly bool) ([]model.Todo, error) {
+	query := s.db.Where("user_id = ?", userID)
+	if pendingOnly {
+		query = query.Where("status = ?", 0)
+	}
+	var todos []model.Todo
+	if err := query.Order("id asc").Find(&todos).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	return todos, nil
+}
+
+// CalendarObject 按资源名获取事项
+func (s *TodoService) CalendarObject(userID int64, name string) (*model.Todo, error) {
+	return findCalendarObject(s.db, userID, name)
+}
+
+// CalendarCTag 日历的整体标签，日历中任一事项新增、修改或删除都会变化
+func (s *TodoService) CalendarCTag(userID int64) (string, error) {
+	var stat struct {
+		Total       int64
+		LastUpdated *time.Time
+		LastDeleted *time.Time
+	}
+	if err := s.db.Unscoped().Model(&model.Todo{}).
+		Select("COUNT(*) AS total, MAX(updated_at) AS last_updated, MAX(deleted_at) AS last_deleted").
+		Where("user_id = ?", userID).
+		Scan(&stat).Error; err != nil {
+		return "", errors.New("查询失败")
+	}
+	// 永久删除不会留下记录，总数变化也需要反映到标签中
+	var live int64
+	if err := s.db.Model(&model.Todo{}).Where("user_id = ?", userID).Count(&live).Error; err != nil {
+		return "", errors.New("查询失败")
+	}
+
+	raw := fmt.Sprintf("%d/%d", stat.Total, live)
+	if stat.LastUpdated != nil {
+		raw += "/" + stat.LastUpdated.UTC().Format(time.RFC3339Nano)
+	}
+	if stat.LastDeleted != nil {
+		raw += "/" + stat.LastDeleted.UTC().Format(time.RFC3339Nano)
+	}
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// CalendarObjectData 将事项编码为只包含一个 VTODO 的 iCalendar 数据，顺序与 todos 一致。
+// 通过 CalDAV 保存的事项返回客户端提交的数据，并合并之后在服务端的修改
+func (s *TodoService) CalendarObjectData(userID int64, todos []model.Todo) ([][]byte, error) {
+	projectNames, err := s.projectNames(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []model.CalendarObject
+	if err := s.db.Where("user_id = ? AND todo_id IN ?", userID, todoIDs(todos)).Find(&objects).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	objectByID := make(map[int64]*model.CalendarObject, len(objects))
+	for i := range objects {
+		objectByID[objects[i].TodoID] = &objects[i]
+	}
+
+	data := make([][]byte, len(todos))
+	for i := range todos {
+		object := objectByID[todos[i].ID]
+		if object != nil && object.Version == todos[i].Version {
+			data[i] = []byte(object.Data)
+			continue
+		}
+
+		var cal *ical.Component
+		if object != nil {
+			cal = mergeCalendarObject(object, &todos[i], projectNames)
+		}
+		if cal == nil {
+			cal = ical.NewCalendar(calendarProdID, "")
+			cal.AddComponent(todoToVTodo(&todos[i], projectNames))
+		}
+		var buf bytes.Buffer
+		if err := ical.Encode(&buf, cal); err != nil {
+			return nil, errors.New("生成日历数据失败")
+		}
+		data[i] = buf.Bytes()
+	}
+	return data, nil
+}
+
+// PutCalendarObject 通过 CalDAV 创建或更新事项，之后按资源名 name 访问。
+// ifMatch 和 ifNoneMatch 为请求中的前置条件，不满足时返回 "资源已被修改" 或 "资源已存在"。
+// 客户端提交的数据原样保存，服务端不支持的属性在返回时保留
+func (s *TodoService) PutCalendarObject(userID int64, name string, data []byte, ifMatch, ifNoneMatch string) (*model.Todo, bool, error) {
+	defer s.changed(userID)
+
+	resource := strings.TrimSuffix(name, ".ics")
+	if resource == "" || len(resource) > 255 {
+		return nil, false, errors.New("无法保存: 资源名无效")
+	}
+	cal, err := ical.Decode(bytes.NewReader(data))
+	if err != nil || cal.Name != "VCALENDAR" {
+		return nil, false, errors.New("日历数据格式错误")
+	}
+	vtodos := cal.Children("VTODO")
+	if len(vtodos) != 1 || len(cal.Children("VEVENT")) > 0 {
+		return nil, false, errors.New("仅支持包含一个 VTODO 的日历数据")
+	}
+	component := vtodos[0]
+	loc, err := s.userLocation(userID)
+	if err != nil {
+		return nil, false, err
+	}
+	candidate, reason, _ := icsComponentToCandidate(component, ical.NewTimezones(cal, loc), time.Now())
+	if reason != "" {
+		return nil, false, errors.New("无法保存: " + reason)
+	}
+
+	var todo *model.Todo
+	created := false
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		existing, err := findCalendarObject(tx, userID, name)
+		if err != nil && err.Error() != "待办事项不存在" {
+			return err
+		}
+
+		// 前置条件
+		if ifNoneMatch == "*" && existing != nil {
+			return errors.New("资源已存在")
+		}
+		if ifMatch != "" && (existing == nil || checkIfMatch(existing, ifMatch) != nil) {
+			return errors.New("资源已被修改")
+		}
+
+		// 清单按分类名称匹配，不存在时创建
+		var projectID int64
+		if candidate.Project != "" {
+			projectIDs, _, err := s.resolveImportProjects(tx, userID, []importCandidate{*candidate}, false)
+			if err != nil {
+				return err
+			}
+			projectID = projectIDs[candidate.Project]
+		}
+
+		txService := s.withDB(tx)
+		if existing == nil {
+			todo, err = txService.createCalendarObject(userID, &candidate.Todo, projectID, resource)
+			created = true
+		} else {
+			todo, err = txService.updateCalendarObject(userID, existing, &candidate.Todo, projectID, ifMatch)
+		}
+		if err != nil {
+			return err
+		}
+		return saveCalendarObject(tx, todo, data)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return todo, created, nil
+}
+
+// DeleteCalendarObject 通过 CalDAV 删除事项（移入回收站）
+func (s *TodoService) DeleteCalendarObject(userID int64, name, ifMatch string) error {
+	defer s.changed(userID)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		existing, err := findCalendarObject(tx, userID, name)
+		if err != nil {
+			return err
+		}
+		if err := checkIfMatch(existing, ifMatch); err != nil {
+			return errors.New("资源已被修改")
+		}
+		return s.withDB(tx).DeleteTodo(userID, existing.ID, ifMatch)
+	})
+}
+
+// createCalendarObject 使用 CalDAV 客户端提交的数据创建事项，resource 为不含 .ics 的资源名
+func (s *TodoService) createCalendarObject(userID int64, parsed *model.Todo, projectID int64, resource string) (*model.Todo, error) {
+	req := model.CreateTodoRequest{
+		Title:    parsed.Title,
+		Content:  parsed.Content,
+		Priority: parsed.Priority,
+	}
+	if parsed.Deadline != nil {
+		req.Deadline = parsed.Deadline.Format(time.RFC3339)
+	}
+	if projectID > 0 {
+		req.ProjectID = &projectID
+	}
+	todo, err := s.CreateTodo(userID, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 记录客户端使用的资源名，之后按该资源名访问（资源名可以与数据中的 UID 不同）
+	if err := s.db.Model(todo).UpdateColumn("external_id", resource).Error; err != nil {
+		return nil, errors.New("创建失败")
+	}
+
+	if parsed.Status == 1 {
+		status := 1
+		return s.UpdateTodo(userID, todo.ID, &model.UpdateTodoRequest{Status: &status}, true, "")
+	}
+	return s.loadTodo(userID, todo.ID)
+}
+
+// updateCalendarObject 使用 CalDAV 客户端提交的数据覆盖事项，ifMatch 不为空时只在事项未被并发修改时更新
+func (s *TodoService) updateCalendarObject(userID int64, existing, parsed *model.Todo, projectID int64, ifMatch string) (*model.Todo, error) {
+	deadline := ""
+	if parsed.Deadline != nil {
+		deadline = parsed.Deadline.Format(time.RFC3339)
+	}
+	req := model.UpdateTodoRequest{
+		Title:     &parsed.Title,
+		Content:   &parsed.Content,
+		Deadline:  &deadline,
+		ProjectID: &projectID,
+		Priority:  &parsed.Priority,
+	}
+	// 状态未变化时不更新，避免重置完成时间
+	if parsed.Status != existing.Status {
+		req.Status = &parsed.Status
+	}
+	// CalDAV 客户端无法指定 force，拒绝后会反复重试同步，因此以客户端提交的状态为准，不检查前置事项
+	return s.UpdateTodo(userID, existing.ID, &req, true, ifMatch)
+}
+
+// calendarSnapshot 保存 CalDAV 数据时事项的字段
+type calendarSnapshot struct {
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Deadline  *int64 `json:"deadline"`
+	Status    int    `json:"status"`
+	Priority  int    `json:"priority"`
+	ProjectID *int64 `json:"project_id"`
+}
+
+// newCalendarSnapshot 记录事项的字段
+func newCalendarSnapshot(todo *model.Todo) calendarSnapshot {
+	snapshot := calendarSnapshot{
+		Title:     todo.Title,
+		Content:   todo.Content,
+		Status:    todo.Status,
+		Priority:  todo.Priority,
+		ProjectID: todo.ProjectID,
+	}
+	if todo.Deadline != nil {
+		deadline := todo.Deadline.Unix()
+		snapshot.Deadline = &deadline
+	}
+	return snapshot
+}
+
+// saveCalendarObject 保存客户端提交的原始数据和保存后事项的字段
+func saveCalendarObject(tx *gorm.DB, todo *model.Todo, data []byte) error {
+	snapshot, err := json.Marshal(newCalendarSnapshot(todo))
+	if err != nil {
+		return errors.New("保存失败")
+	}
+	object := model.CalendarObject{
+		TodoID:   todo.ID,
+		UserID:   todo.UserID,
+		Data:     string(data),
+		Version:  todo.Version,
+		Snapshot: string(snapshot),
+	}
+	if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&object).Error; err != nil {
+		return errors.New("保存失败")
+	}
+	return nil
+}
+
+// mergeCalendarObject 将事项在服务端的修改合并到客户端提交的数据中，只替换被修改的字段对应的属性，
+// 其余属性（包括重复规则和提醒）保持不变。数据无法解析时返回 nil
+func mergeCalendarObject(object *model.CalendarObject, todo *model.Todo, projectNames map[int64]string) *ical.Component {
+	cal, err := ical.Decode(strings.NewReader(object.Data))
+	if err != nil {
+		return nil
+	}
+	vtodos := cal.Children("VTODO")
+	var before calendarSnapshot
+	if len(vtodos) != 1 || json.Unmarshal([]byte(object.Snapshot), &before) != nil {
+		return nil
+	}
+	after := newCalendarSnapshot(todo)
+
+	replaced := []string{"DTSTAMP", "LAST-MODIFIED"}
+	if after.Title != before.Title {
+		replaced = append(replaced, "SUMMARY")
+	}
+	if after.Content != before.Content {
+		replaced = append(replaced, "DESCRIPTION")
+	}
+	if !equalInt64Ptr(after.Deadline, before.Deadline) {
+		replaced = append(replaced, "DUE")
+	}
+	if after.Status != before.Status {
+		replaced = append(replaced, "STATUS", "PERCENT-COMPLETE", "COMPLETED")
+	}
+	if after.Priority != before.Priority {
+		replaced = append(replaced, "PRIORITY")
+	}
+	if !equalInt64Ptr(after.ProjectID, before.ProjectID) {
+		replaced = append(replaced, "CATEGORIES")
+	}
+
+	vtodo := vtodos[0]
+	generated := todoToVTodo(todo, projectNames)
+	for _, name := range replaced {
+		vtodo.Remove(name)
+		for _, prop := range generated.Properties {
+			if prop.Name == name {
+				vtodo.Properties = append(vtodo.Properties, prop)
+			}
+		}
+	}
+	return cal
+}
+
+// equalInt64Ptr 比较两个可为空的整数
+func equalInt64Ptr(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// findCalendarObject 按资源名查找事项，资源名为事项的导出标识加 .ics
+func findCalendarObject(db *gorm.DB, userID int64, name string) (*model.Todo, error) {
+	uid := strings.TrimSuffix(name, ".ics")
+	if uid == "" {
+		return nil, errors.New("待办事项不存在")
+	}
+
+	var todo model.Todo
+	err := db.Where("user_id = ? AND external_id = ?", userID, uid).Order("id asc").First(&todo).Error
+	if err == gorm.ErrRecordNotFound {
+		id, ok := nativeUID(uid)
+		if !ok {
+			return nil, errors.New("待办事项不存在")
+		}
+		err = db.Where("id = ? AND user_id = ? AND external_id = ?", id, userID, "").First(&todo).Error
+	}
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("待办事项不存在")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return &todo, nil
+}
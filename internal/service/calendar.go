@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"RemindGo/internal/ical"
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// calendarProdID 生成日历时使用的 PRODID
+const calendarProdID = "-//RemindGo//RemindGo Calendar//ZH"
+
+// CalendarService 日历服务
+type CalendarService struct {
+	db *gorm.DB
+}
+
+// NewCalendarService 创建日历服务
+func NewCalendarService(db *gorm.DB) *CalendarService {
+	return &CalendarService{db: db}
+}
+
+// GetFeed 获取用户的日历订阅
+func (s *CalendarService) GetFeed(userID int64) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	if err := s.db.Where("user_id = ?", userID).First(&feed).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("尚未创建日历订阅")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return &feed, nil
+}
+
+// ResetFeed 创建日历订阅，已存在时重新生成令牌，旧的订阅地址随即失效
+func (s *CalendarService) ResetFeed(userID int64) (*model.CalendarFeed, error) {
+	token, err := randomToken(24)
+	if err != nil {
+		return nil, errors.New("创建订阅失败")
+	}
+
+	var feed model.CalendarFeed
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ?", userID).First(&feed).Error
+		if err == gorm.ErrRecordNotFound {
+			feed = model.CalendarFeed{UserID: userID, Token: token}
+			return tx.Create(&feed).Error
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&feed).Update("token", token).Error
+	})
+	if err != nil {
+		return nil, errors.New("创建订阅失败")
+	}
+	return &feed, nil
+}
+
+// RevokeFeed 撤销日历订阅
+func (s *CalendarService) RevokeFeed(userID int64) error {
+	result := s.db.Where("user_id = ?", userID).Delete(&model.CalendarFeed{})
+	if result.Error != nil {
+		return errors.New("撤销失败")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("尚未创建日历订阅")
+	}
+	return nil
+}
+
+// WriteFeed 按订阅令牌生成包含截止时间的事项的日历，写入 w
+func (s *CalendarService) WriteFeed(token string, params *model.CalendarFeedParams, w io.Writer) error {
+	if params.Type == "" {
+		params.Type = "event"
+	}
+	if params.Type != "event" && params.Type != "todo" {
+		return errors.New("日历类型无效")
+	}
+
+	var feed model.CalendarFeed
+	if token == "" {
+		return errors.New("订阅地址无效")
+	}
+	if err := s.db.Where("token = ?", token).First(&feed).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New("订阅地址无效")
+		}
+		return errors.New("查询失败")
+	}
+
+	query := s.db.Where("user_id = ? AND deadline IS NOT NULL", feed.UserID)
+	if params.Status == "pending" {
+		query = query.Where("status = ?", 0)
+	} else if params.Status == "completed" {
+		query = query.Where("status = ?", 1)
+	}
+	if params.ProjectID > 0 {
+		query = query.Where("project_id = ?", params.ProjectID)
+	}
+
+	var todos []model.Todo
+	if err := query.Order("deadline asc, id asc").Find(&todos).Error; err != nil {
+		return errors.New("查询失败")
+	}
+
+	var projects []model.Project
+	if err := s.db.Where("user_id = ?", feed.UserID).Find(&projects).Error; err != nil {
+		return errors.New("查询失败")
+	}
+	projectNames := make(map[int64]string, len(projects))
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+
+	cal := ical.NewCalendar(calendarProdID, "RemindGo")
+	for i := range todos {
+		if params.Type == "todo" {
+			cal.AddComponent(todoToVTodo(&todos[i], projectNames))
+		} else {
+			cal.AddComponent(todoToVEvent(&todos[i], projectNames))
+		}
+	}
+	return ical.Encode(w, cal)
+}
+
+// todoToVTodo 将待办事项转换为 VTODO 组件
+func todoToVTodo(todo *model.Todo, projectNames map[int64]string) *ical.Component {
+	c := ical.NewComponent("VTODO")
+	addCommonProperties(c, todo, projectNames)
+	if todo.Deadline != nil {
+		c.AddDateTime("DUE", *todo.Deadline)
+	}
+	if todo.Status == 1 {
+		c.Add("STATUS", "COMPLETED")
+		c.Add("PERCENT-COMPLETE", "100")
+		if todo.CompletedAt != nil {
+			c.AddDateTime("COMPLETED", *todo.CompletedAt)
+		}
+	} else {
+		c.Add("STATUS", "NEEDS-ACTION")
+	}
+	return c
+}
+
+// todoToVEvent 将待办事项转换为 VEVENT 组件，事件时间为截止时间
+func todoToVEvent(todo *model.Todo, projectNames map[int64]string) *ical.Component {
+	c := ical.NewComponent("VEVENT")
+	addCommonProperties(c, todo, projectNames)
+	if todo.Deadline != nil {
+		c.AddDateTime("DTSTART", *todo.Deadline)
+	}
+	c.Add("TRANSP", "TRANSPARENT")
+	c.Add("STATUS", "CONFIRMED")
+	return c
+}
+
+// addCommonProperties 添加 VTODO 和 VEVENT 共有的属性
+func addCommonProperties(c *ical.Component, todo *model.Todo, projectNames map[int64]string) {
+	c.AddText("UID", exportUID(todo))
+	// 发布的日历没有 METHOD，DTSTAMP 表示事项最后修改的时间
+	c.AddDateTime("DTSTAMP", todo.UpdatedAt)
+	c.AddDateTime("CREATED", todo.CreatedAt)
+	c.AddDateTime("LAST-MODIFIED", todo.UpdatedAt)
+	c.AddText("SUMMARY", todo.Title)
+	if todo.Content != "" {
+		c.AddText("DESCRIPTION", todo.Content)
+	}
+	if priority := icalPriority(todo.Priority); priority > 0 {
+		c.Add("PRIORITY", strconv.Itoa(priority))
+	}
+	if todo.ProjectID != nil {
+		if name := projectNames[*todo.ProjectID]; name != "" {
+			c.AddText("CATEGORIES", name)
+		}
+	}
+}
+
+// icalPriority 将优先级转换为 iCalendar 的 PRIORITY（1 最高，9 最低，0 未定义）
+func icalPriority(priority int) int {
+	switch priority {
+	case model.PriorityUrgent:
+		return 1
+	case model.PriorityHigh:
+		return 3
+	case model.PriorityMedium:
+		return 5
+	case model.PriorityLow:
+		return 9
+	}
+	return 0
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// GetChecklist 获取待办事项的检查项列表
+func (s *TodoService) GetChecklist(userID, todoID int64) ([]model.ChecklistItem, error) {
+	if _, err := s.findTodo(s.db, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	var items []model.ChecklistItem
+	if err := s.db.Where("todo_id = ?", todoID).
+		Order("sort_order asc, id asc").
+		Find(&items).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	return items, nil
+}
+
+// AddChecklistItem 添加检查项，新检查项排在最后
+func (s *TodoService) AddChecklistItem(userID, todoID int64, req *model.CreateChecklistItemRequest) (*model.ChecklistItem, error) {
+	defer s.changed(userID)
+
+	var item model.ChecklistItem
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.findTodo(tx, userID, todoID); err != nil {
+			return err
+		}
+
+		var maxOrder *int
+		if err := tx.Model(&model.ChecklistItem{}).Where("todo_id = ?", todoID).
+			Select("MAX(sort_order)").Scan(&maxOrder).Error; err != nil {
+			return errors.New("创建失败")
+		}
+
+		item = model.ChecklistItem{
+			TodoID: todoID,
+			Title:  req.Title,
+		}
+		if maxOrder != nil {
+			item.SortOrder = *maxOrder + 1
+		}
+
+		if err := tx.Create(&item).Error; err != nil {
+			return errors.New("创建失败")
+		}
+		if err := touchTodos(tx, userID, "checklist", todoID); err != nil {
+			return err
+		}
+		// 新的检查项未完成，已自动完成的父事项需要重新打开
+		return s.syncChecklistParent(tx, userID, todoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// UpdateChecklistItem 更新检查项
+func (s *TodoService) UpdateChecklistItem(userID, todoID, itemID int64, req *model.UpdateChecklistItemRequest) (*model.ChecklistItem, error) {
+	defer s.changed(userID)
+
+	var item *model.ChecklistItem
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		item, err = s.findChecklistItem(tx, userID, todoID, itemID)
+		if err != nil {
+			return err
+		}
+
+		if req.Title == nil {
+			return nil
+		}
+		if err := tx.Model(item).Update("title", *req.Title).Error; err != nil {
+			return errors.New("更新失败")
+		}
+		return touchTodos(tx, userID, "checklist", todoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ToggleChecklistItem 切换检查项完成状态，并在开启自动完成时同步父待办事项状态
+func (s *TodoService) ToggleChecklistItem(userID, todoID, itemID int64) (*model.ChecklistItem, *model.Todo, error) {
+	defer s.changed(userID)
+
+	var item *model.ChecklistItem
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		item, err = s.findChecklistItem(tx, userID, todoID, itemID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(item).Update("done", !item.Done).Error; err != nil {
+			return errors.New("更新失败")
+		}
+		if err := touchTodos(tx, userID, "checklist", todoID); err != nil {
+			return err
+		}
+
+		return s.syncChecklistParent(tx, userID, todoID)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	todo, err := s.loadTodo(userID, todoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, todo, nil
+}
+
+// ReorderChecklist 按给定ID顺序重新排列检查项
+func (s *TodoService) ReorderChecklist(userID, todoID int64, itemIDs []int64) ([]model.ChecklistItem, error) {
+	defer s.changed(userID)
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.findTodo(tx, userID, todoID); err != nil {
+			return err
+		}
+
+		var existing []int64
+		if err := tx.Model(&model.ChecklistItem{}).Where("todo_id = ?", todoID).
+			Pluck("id", &existing).Error; err != nil {
+			return errors.New("查询失败")
+		}
+
+		// 必须恰好包含全部检查项
+		if len(existing) != len(itemIDs) {
+			return errors.New("检查项列表不完整")
+		}
+		known := make(map[int64]bool, len(existing))
+		for _, id := range existing {
+			known[id] = true
+		}
+		for _, id := range itemIDs {
+			if !known[id] {
+				return errors.New("检查项列表不完整")
+			}
+			delete(known, id)
+		}
+
+		for i, id := range itemIDs {
+			if err := tx.Model(&model.ChecklistItem{}).Where("id = ?", id).
+				Update("sort_order", i).Error; err != nil {
+				return errors.New("更新失败")
+			}
+		}
+		return touchTodos(tx, userID, "checklist", todoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetChecklist(userID, todoID)
+}
+
+// DeleteChecklistItem 删除检查项
+func (s *TodoService) DeleteChecklistItem(userID, todoID, itemID int64) error {
+	defer s.changed(userID)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		item, err := s.findChecklistItem(tx, userID, todoID, itemID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(item).Error; err != nil {
+			return errors.New("删除失败")
+		}
+		if err := touchTodos(tx, userID, "checklist", todoID); err != nil {
+			return err
+		}
+		// 删除唯一未完成的检查项后，其余检查项可能已全部完成
+		return s.syncChecklistParent(tx, userID, todoID)
+	})
+}
+
+// syncChecklistParent 开启自动完成时，根据检查项状态同步父待办事项：
+// 全部完成则完成父事项（存在未完成的前置事项时不完成），存在未完成项则重新打开父事项
+func (s *TodoService) syncChecklistParent(tx *gorm.DB, userID, todoID int64) error {
+	todo, err := s.findTodo(tx, userID, todoID)
+	if err != nil {
+		return err
+	}
+	if !todo.ChecklistAutoComplete {
+		return nil
+	}
+
+	var total, done int64
+	if err := tx.Model(&model.ChecklistItem{}).Where("todo_id = ?", todoID).Count(&total).Error; err != nil {
+		return errors.New("查询失败")
+	}
+	if err := tx.Model(&model.ChecklistItem{}).Where("todo_id = ? AND done = ?", todoID, true).Count(&done).Error; err != nil {
+		return errors.New("查询失败")
+	}
+
+	updates := make(map[string]interface{})
+	if total > 0 && done == total && todo.Status == 0 {
+		blocked, err := s.hasPendingBlockers(tx, todoID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return nil
+		}
+		now := time.Now()
+		updates["status"] = 1
+		updates["completed_at"] = &now
+	} else if done < total && todo.Status == 1 {
+		updates["status"] = 0
+		updates["completed_at"] = nil
+	}
+
+	if len(updates) > 0 {
+		before := *todo
+		if err := updateTodoRow(tx, todo, updates, ""); err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, todo, updateAction(&before, todo), "checklist", diffTodo(&before, todo))
+	}
+	return nil
+}
+
+// findTodo 在指定会话中查询属于用户的待办事项
+func (s *TodoService) findTodo(tx *gorm.DB, userID, todoID int64) (*model.Todo, error) {
+	var todo model.Todo
+	if err := tx.Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("待办事项不存在")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return &todo, nil
+}
+
+// findChecklistItem 查询属于用户待办事项的检查项
+func (s *TodoService) findChecklistItem(tx *gorm.DB, userID, todoID, itemID int64) (*model.ChecklistItem, error) {
+	if _, err := s.findTodo(tx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	var item model.ChecklistItem
+	if err := tx.Where("id = ? AND todo_id = ?", itemID, todoID).First(&item).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("检查项不存在")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return &item, nil
+}
+
+// ChecklistProgress 计算检查项完成进度，格式为 已完成数/总数
+func ChecklistProgress(items []model.ChecklistItem) string {
+	done := 0
+	for _, item := range items {
+		if item.Done {
+			done++
+		}
+	}
+	return fmt.Sprintf("%d/%d", done, len(items))
+}
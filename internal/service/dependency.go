@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pendingBlockerSQL 判断待办事项是否存在未完成的前置事项
+const pendingBlockerSQL = "EXISTS (SELECT 1 FROM todo_dependencies d JOIN todos b ON b.id = d.blocked_by_id" +
+	" WHERE d.todo_id = todos.id AND b.status = 0 AND b.deleted_at IS NULL)"
+
+// AddDependency 为待办事项添加前置事项，会拒绝形成循环的依赖
+func (s *TodoService) AddDependency(userID, todoID, blockedByID int64) (*model.Todo, error) {
+	defer s.changed(userID)
+
+	if todoID == blockedByID {
+		return nil, errors.New("不能依赖自身")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 串行化同一用户的依赖变更，避免并发写入形成循环
+		if err := lockDependencies(tx, userID); err != nil {
+			return err
+		}
+
+		if _, err := s.findTodo(tx, userID, todoID); err != nil {
+			return err
+		}
+		if _, err := s.findTodo(tx, userID, blockedByID); err != nil {
+			if err.Error() == "待办事项不存在" {
+				return errors.New("前置事项不存在")
+			}
+			return err
+		}
+
+		var edges []model.TodoDependency
+		if err := tx.Where("user_id = ?", userID).Find(&edges).Error; err != nil {
+			return errors.New("查询失败")
+		}
+
+		graph := make(map[int64][]int64)
+		for _, edge := range edges {
+			if edge.TodoID == todoID && edge.BlockedByID == blockedByID {
+				return errors.New("依赖关系已存在")
+			}
+			graph[edge.TodoID] = append(graph[edge.TodoID], edge.BlockedByID)
+		}
+		if dependsOn(graph, blockedByID, todoID) {
+			return errors.New("依赖关系存在循环")
+		}
+
+		dependency := model.TodoDependency{
+			UserID:      userID,
+			TodoID:      todoID,
+			BlockedByID: blockedByID,
+		}
+		if err := tx.Create(&dependency).Error; err != nil {
+			return errors.New("创建失败")
+		}
+		// 两端事项的 blocked_by 和 blocking 都发生了变化
+		return touchTodos(tx, userID, "dependency", todoID, blockedByID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadTodo(userID, todoID)
+}
+
+// RemoveDependency 移除待办事项的前置事项
+func (s *TodoService) RemoveDependency(userID, todoID, blockedByID int64) (*model.Todo, error) {
+	defer s.changed(userID)
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND todo_id = ? AND blocked_by_id = ?", userID, todoID, blockedByID).
+			Delete(&model.TodoDependency{})
+		if result.Error != nil {
+			return errors.New("删除失败")
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("依赖关系不存在")
+		}
+		return touchTodos(tx, userID, "dependency", todoID, blockedByID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadTodo(userID, todoID)
+}
+
+// lockDependencies 锁定用户行，串行化同一用户的依赖变更和依赖检查，需要在事务中调用
+func lockDependencies(tx *gorm.DB, userID int64) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").First(&model.User{}, userID).Error; err != nil {
+		return errors.New("查询失败")
+	}
+	return nil
+}
+
+// pendingBlockedIDs 返回一起完成 ids 中的事项时仍存在未完成前置事项的事项。
+// 同时完成的前置事项不算未完成；被阻塞的事项不会完成，因此反复排除直到没有新的阻塞
+func pendingBlockedIDs(tx *gorm.DB, ids []int64) (map[int64]bool, error) {
+	blocked := make(map[int64]bool)
+	completing := ids
+	for len(completing) > 0 {
+		var found []int64
+		if err := tx.Model(&model.TodoDependency{}).
+			Joins("JOIN todos b ON b.id = todo_dependencies.blocked_by_id").
+			Where("todo_dependencies.todo_id IN ? AND b.id NOT IN ? AND b.status = 0 AND b.deleted_at IS NULL", completing, completing).
+			Distinct().Pluck("todo_dependencies.todo_id", &found).Error; err != nil {
+			return nil, errors.New("查询失败")
+		}
+		if len(found) == 0 {
+			break
+		}
+		for _, id := range found {
+			blocked[id] = true
+		}
+		remaining := make([]int64, 0, len(completing))
+		for _, id := range completing {
+			if !blocked[id] {
+				remaining = append(remaining, id)
+			}
+		}
+		completing = remaining
+	}
+	return blocked, nil
+}
+
+// excludeTodos 去掉 ids 中的事项
+func excludeTodos(todos []model.Todo, ids map[int64]bool) []model.Todo {
+	if len(ids) == 0 {
+		return todos
+	}
+	kept := make([]model.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if !ids[todo.ID] {
+			kept = append(kept, todo)
+		}
+	}
+	return kept
+}
+
+// hasPendingBlockers 检查待办事项是否存在未完成的前置事项
+func (s *TodoService) hasPendingBlockers(tx *gorm.DB, todoID int64) (bool, error) {
+	var count int64
+	if err := tx.Model(&model.Todo{}).Where("id = ?", todoID).Where(pendingBlockerSQL).
+		Count(&count).Error; err != nil {
+		return false, errors.New("查询失败")
+	}
+	return count > 0, nil
+}
+
+// dependsOn 沿前置关系遍历依赖图，判断 from 是否（间接）依赖 target
+func dependsOn(graph map[int64][]int64, from, target int64) bool {
+	visited := map[int64]bool{from: true}
+	queue := []int64{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == target {
+			return true
+		}
+		for _, next := range graph[current] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// BlockedByIDs 获取待办事项的前置事项ID
+func BlockedByIDs(todo *model.Todo) []int64 {
+	ids := make([]int64, len(todo.BlockedBy))
+	for i, dependency := range todo.BlockedBy {
+		ids[i] = dependency.BlockedByID
+	}
+	return ids
+}
+
+// BlockingIDs 获取被待办事项阻塞的后续事项ID
+func BlockingIDs(todo *model.Todo) []int64 {
+	ids := make([]int64, len(todo.Blocking))
+	for i, dependency := range todo.Blocking {
+		ids[i] = dependency.TodoID
+	}
+	return ids
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines unified diff 中每个变更块保留的上下文行数
+const diffContextLines = 3
+
+// diffOp 编辑脚本中的一行：' ' 表示相同，'-' 表示删除，'+' 表示新增
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff 生成两段文本的 unified diff，文本相同时返回空字符串
+func unifiedDiff(fromName, toName, from, to string) string {
+	a := splitLines(from)
+	b := splitLines(to)
+	ops := diffLines(a, b)
+
+	var out strings.Builder
+	aLine, bLine := 0, 0 // 当前操作对应的行号（从0开始）
+	i := 0
+	for i < len(ops) {
+		// 跳到下一处变更
+		if ops[i].kind == ' ' {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+
+		// 向前包含上下文
+		start := i
+		for start > 0 && i-start < diffContextLines && ops[start-1].kind == ' ' {
+			start--
+		}
+		hunkA := aLine - (i - start)
+		hunkB := bLine - (i - start)
+
+		// 向后扩展，直到连续相同行超过两倍上下文
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := 0
+			for end+run < len(ops) && ops[end+run].kind == ' ' {
+				run++
+			}
+			if end+run == len(ops) || run > 2*diffContextLines {
+				if run > diffContextLines {
+					run = diffContextLines
+				}
+				end += run
+				break
+			}
+			end += run
+		}
+
+		var countA, countB int
+		var body strings.Builder
+		for _, op := range ops[start:end] {
+			body.WriteByte(op.kind)
+			body.WriteString(op.text)
+			body.WriteByte('\n')
+			if op.kind != '+' {
+				countA++
+			}
+			if op.kind != '-' {
+				countB++
+			}
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(hunkA, countA), hunkRange(hunkB, countB))
+		out.WriteString(body.String())
+
+		// 推进行号
+		for _, op := range ops[i:end] {
+			if op.kind != '+' {
+				aLine++
+			}
+			if op.kind != '-' {
+				bLine++
+			}
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// hunkRange 格式化变更块的行范围，空范围的起始行为前一行
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// diffLines 基于最长公共子序列计算逐行编辑脚本。先去掉相同的首尾行，
+// 中间部分使用 Hirschberg 算法，只需要线性的额外空间
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = diffMiddle(ops, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// diffMiddle 将 a 和 b 从中间一分为二递归计算编辑脚本，追加到 ops
+func diffMiddle(ops []diffOp, a, b []string) []diffOp {
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	case len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		return ops
+	case len(a) == 1:
+		for j, line := range b {
+			if line == a[0] {
+				for _, added := range b[:j] {
+					ops = append(ops, diffOp{'+', added})
+				}
+				ops = append(ops, diffOp{' ', line})
+				for _, added := range b[j+1:] {
+					ops = append(ops, diffOp{'+', added})
+				}
+				return ops
+			}
+		}
+		ops = append(ops, diffOp{'-', a[0]})
+		for _, added := range b {
+			ops = append(ops, diffOp{'+', added})
+		}
+		return ops
+	}
+
+	// 找到 b 的分割点，使两半的公共子序列长度之和最大
+	mid := len(a) / 2
+	head := lcsPrefixLengths(a[:mid], b)
+	tail := lcsSuffixLengths(a[mid:], b)
+	split, best := 0, -1
+	for j := 0; j <= len(b); j++ {
+		if head[j]+tail[j] > best {
+			split, best = j, head[j]+tail[j]
+		}
+	}
+
+	ops = diffMiddle(ops, a[:mid], b[:split])
+	return diffMiddle(ops, a[mid:], b[split:])
+}
+
+// lcsPrefixLengths 返回 a 与 b 的每个前缀 b[:j] 的最长公共子序列长度
+func lcsPrefixLengths(a, b []string) []int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// lcsSuffixLengths 返回 a 与 b 的每个后缀 b[j:] 的最长公共子序列长度
+func lcsSuffixLengths(a, b []string) []int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				cur[j] = prev[j+1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j+1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// splitLines 按行拆分文本，空文本没有行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
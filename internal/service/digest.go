@@ -0,0 +1,415 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"RemindGo/internal/digest"
+	"RemindGo/internal/mail"
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// DigestCheckInterval 检查是否有需要发送的摘要的间隔
+var DigestCheckInterval = time.Minute
+
+// digestSectionLimit 摘要中每组最多列出的事项数
+const digestSectionLimit = 20
+
+// digestPriorityNames 摘要中显示的优先级名称
+var digestPriorityNames = map[int]string{
+	model.PriorityLow:    "低优先级",
+	model.PriorityMedium: "中优先级",
+	model.PriorityHigh:   "高优先级",
+	model.PriorityUrgent: "紧急",
+}
+
+// DigestService 摘要邮件服务
+type DigestService struct {
+	db      *gorm.DB
+	sender  mail.Sender
+	baseURL string // 邮件中链接的地址前缀，如 https://remindgo.example.com
+}
+
+// NewDigestService 创建摘要邮件服务
+func NewDigestService(db *gorm.DB, sender mail.Sender, baseURL string) *DigestService {
+	return &DigestService{
+		db:      db,
+		sender:  sender,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// GetSetting 获取用户的摘要设置
+func (s *DigestService) GetSetting(userID int64) (*model.DigestSettingResponse, error) {
+	setting, err := s.ensureSetting(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.settingToResponse(setting)
+}
+
+// ensureSetting 读取用户的摘要设置，不存在时创建默认设置（不发送）
+func (s *DigestService) ensureSetting(userID int64) (*model.DigestSetting, error) {
+	var setting model.DigestSetting
+	err := s.db.Where("user_id = ?", userID).First(&setting).Error
+	if err == nil {
+		return &setting, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, errors.New("查询失败")
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return nil, errors.New("生成退订令牌失败")
+	}
+	setting = model.DigestSetting{
+		UserID:           userID,
+		Frequency:        model.DigestOff,
+		SendHour:         8,
+		Weekday:          int(time.Monday),
+		UnsubscribeToken: token,
+	}
+	if err := s.db.Create(&setting).Error; err != nil {
+		// 并发创建时读取已创建的设置
+		if err := s.db.Where("user_id = ?", userID).First(&setting).Error; err != nil {
+			return nil, errors.New("创建失败")
+		}
+	}
+	return &setting, nil
+}
+
+// UpdateSetting 更新摘要设置
+func (s *DigestService) UpdateSetting(userID int64, req *model.UpdateDigestSettingRequest) (*model.DigestSettingResponse, error) {
+	updates := make(map[string]interface{})
+	if req.Frequency != nil {
+		switch *req.Frequency {
+		case model.DigestOff, model.DigestDaily, model.DigestWeekly:
+			updates["frequency"] = *req.Frequency
+		default:
+			return nil, errors.New("摘要频率无效")
+		}
+	}
+	if req.SendHour != nil {
+		if *req.SendHour < 0 || *req.SendHour > 23 {
+			return nil, errors.New("发送时间无效")
+		}
+		updates["send_hour"] = *req.SendHour
+	}
+	if req.Weekday != nil {
+		if *req.Weekday < 0 || *req.Weekday > 6 {
+			return nil, errors.New("发送日无效")
+		}
+		updates["weekday"] = *req.Weekday
+	}
+
+	setting, err := s.ensureSetting(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) > 0 {
+		if err := s.db.Model(setting).Updates(updates).Error; err != nil {
+			return nil, errors.New("更新失败")
+		}
+	}
+	return s.settingToResponse(setting)
+}
+
+// settingToResponse 转换摘要设置，附带用户时区
+func (s *DigestService) settingToResponse(setting *model.DigestSetting) (*model.DigestSettingResponse, error) {
+	var user model.User
+	if err := s.db.Select("time_zone").First(&user, setting.UserID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	resp := &model.DigestSettingResponse{
+		Frequency: setting.Frequency,
+		SendHour:  setting.SendHour,
+		Weekday:   setting.Weekday,
+		TimeZone:  user.TimeZone,
+	}
+	if resp.TimeZone == "" {
+		resp.TimeZone = "UTC"
+	}
+	if setting.LastSentAt != nil {
+		sentAt := setting.LastSentAt.Unix()
+		resp.LastSentAt = &sentAt
+	}
+	return resp, nil
+}
+
+// CheckUnsubscribeToken 检查退订链接是否有效，用于显示退订确认页面
+func (s *DigestService) CheckUnsubscribeToken(token string) error {
+	_, err := s.findByUnsubscribeToken(token)
+	return err
+}
+
+// Unsubscribe 通过邮件中的退订链接关闭摘要
+func (s *DigestService) Unsubscribe(token string) error {
+	setting, err := s.findByUnsubscribeToken(token)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Model(setting).Update("frequency", model.DigestOff).Error; err != nil {
+		return errors.New("退订失败")
+	}
+	return nil
+}
+
+// findByUnsubscribeToken 根据退订令牌查询摘要设置
+func (s *DigestService) findByUnsubscribeToken(token string) (*model.DigestSetting, error) {
+	var setting model.DigestSetting
+	if err := s.db.Where("unsubscribe_token = ?", token).First(&setting).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("退订链接无效")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return &setting, nil
+}
+
+// Preview 渲染摘要但不发送，frequency 为空时使用当前设置，未开启时按每日摘要渲染
+func (s *DigestService) Preview(userID int64, frequency string) (*model.DigestPreviewResponse, error) {
+	setting, err := s.ensureSetting(userID)
+	if err != nil {
+		return nil, err
+	}
+	if frequency == "" {
+		frequency = setting.Frequency
+		if frequency == model.DigestOff {
+			frequency = model.DigestDaily
+		}
+	}
+	if frequency != model.DigestDaily && frequency != model.DigestWeekly {
+		return nil, errors.New("摘要频率无效")
+	}
+
+	var user model.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	d, err := s.buildDigest(&user, setting, frequency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	html, text, err := digest.Render(d)
+	if err != nil {
+		return nil, errors.New("渲染摘要失败")
+	}
+	return &model.DigestPreviewResponse{
+		Subject: d.Subject(),
+		HTML:    html,
+		Text:    text,
+		Empty:   d.Empty(),
+	}, nil
+}
+
+// SendDueDigests 发送所有到达发送时间的摘要，返回发送的数量。
+// 每个周期只发送一次，没有任何事项时不发送；多个实例同时运行时通过条件更新避免重复发送
+func (s *DigestService) SendDueDigests(now time.Time) (int, error) {
+	var settings []model.DigestSetting
+	sent := 0
+	result := s.db.Where("frequency <> ?", model.DigestOff).Order("id asc").
+		FindInBatches(&settings, 100, func(tx *gorm.DB, batch int) error {
+			userIDs := make([]int64, len(settings))
+			for i, setting := range settings {
+				userIDs[i] = setting.UserID
+			}
+			var users []model.User
+			if err := s.db.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+				return err
+			}
+			usersByID := make(map[int64]*model.User, len(users))
+			for i := range users {
+				usersByID[users[i].ID] = &users[i]
+			}
+
+			for i := range settings {
+				user := usersByID[settings[i].UserID]
+				if user == nil {
+					continue
+				}
+				ok, err := s.sendDigest(user, &settings[i], now)
+				if err != nil {
+					log.Printf("Failed to send digest to user %d: %v", user.ID, err)
+					continue
+				}
+				if ok {
+					sent++
+				}
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return sent, errors.New("查询失败")
+	}
+	return sent, nil
+}
+
+// sendDigest 到达发送时间时发送用户的摘要，返回是否发送了邮件
+func (s *DigestService) sendDigest(user *model.User, setting *model.DigestSetting, now time.Time) (bool, error) {
+	loc, err := LoadTimeZone(user.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	if local.Hour() < setting.SendHour {
+		return false, nil
+	}
+	if setting.Frequency == model.DigestWeekly && int(local.Weekday()) != setting.Weekday {
+		return false, nil
+	}
+	today := localDate(now, loc)
+	if setting.LastSentAt != nil && !setting.LastSentAt.Before(today) {
+		return false, nil
+	}
+
+	// 先占用本周期，其他实例的条件更新会失败
+	claim := s.db.Model(&model.DigestSetting{}).
+		Where("id = ? AND (last_sent_at IS NULL OR last_sent_at < ?)", setting.ID, today).
+		Update("last_sent_at", now)
+	if claim.Error != nil {
+		return false, claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return false, nil
+	}
+
+	d, err := s.buildDigest(user, setting, setting.Frequency, now)
+	if err != nil || d.Empty() {
+		return false, err
+	}
+	html, text, err := digest.Render(d)
+	if err != nil {
+		return false, err
+	}
+	err = s.sender.Send(&mail.Message{
+		To:      user.Email,
+		Subject: d.Subject(),
+		HTML:    html,
+		Text:    text,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + d.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+	if err != nil {
+		// 发送失败时恢复上次发送时间，下次检查时重试
+		s.db.Model(&model.DigestSetting{}).Where("id = ?", setting.ID).Update("last_sent_at", setting.LastSentAt)
+		return false, err
+	}
+	return true, nil
+}
+
+// buildDigest 生成摘要内容。每日摘要包括今天截止、已逾期和昨天完成的事项，
+// 每周摘要包括未来7天截止、已逾期和过去7天完成的事项
+func (s *DigestService) buildDigest(user *model.User, setting *model.DigestSetting, frequency string, now time.Time) (*digest.Digest, error) {
+	loc, err := LoadTimeZone(user.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	today := localDate(now, loc)
+
+	period, dueTitle, doneTitle := "今日摘要", "今天截止", "昨天完成"
+	dueEnd, doneStart := today.AddDate(0, 0, 1), today.AddDate(0, 0, -1)
+	if frequency == model.DigestWeekly {
+		period, dueTitle, doneTitle = "本周摘要", "未来7天截止", "过去7天完成"
+		dueEnd, doneStart = today.AddDate(0, 0, 7), today.AddDate(0, 0, -7)
+	}
+
+	projectNames := make(map[int64]string)
+	var projects []model.Project
+	if err := s.db.Where("user_id = ?", user.ID).Find(&projects).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+
+	scope := func() *gorm.DB {
+		return s.db.Model(&model.Todo{}).Where("user_id = ?", user.ID)
+	}
+	due, err := s.digestSection(dueTitle, scope().
+		Where("status = ? AND deadline >= ? AND deadline < ?", 0, today, dueEnd).
+		Order("deadline asc"), projectNames, loc)
+	if err != nil {
+		return nil, err
+	}
+	overdue, err := s.digestSection("已逾期", scope().
+		Where("status = ? AND deadline < ?", 0, today).
+		Order("deadline asc"), projectNames, loc)
+	if err != nil {
+		return nil, err
+	}
+	done, err := s.digestSection(doneTitle, scope().
+		Where("status = ? AND completed_at >= ? AND completed_at < ?", 1, doneStart, today).
+		Order("completed_at desc"), projectNames, loc)
+	if err != nil {
+		return nil, err
+	}
+	// 已完成的事项不显示截止时间
+	for i := range done.Items {
+		done.Items[i].Deadline = ""
+	}
+
+	return &digest.Digest{
+		Username:       user.Username,
+		Period:         period,
+		Date:           today.Format("2006年1月2日"),
+		Sections:       []digest.Section{due, overdue, done},
+		AppURL:         s.baseURL + "/",
+		UnsubscribeURL: s.baseURL + "/api/v1/digest/unsubscribe/" + setting.UnsubscribeToken,
+	}, nil
+}
+
+// digestSection 查询摘要中的一组事项，最多列出 digestSectionLimit 项
+func (s *DigestService) digestSection(title string, query *gorm.DB, projectNames map[int64]string, loc *time.Location) (digest.Section, error) {
+	section := digest.Section{Title: title}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return section, errors.New("查询失败")
+	}
+	section.Total = int(total)
+
+	var todos []model.Todo
+	if err := query.Limit(digestSectionLimit).Find(&todos).Error; err != nil {
+		return section, errors.New("查询失败")
+	}
+	for _, todo := range todos {
+		item := digest.Item{Title: todo.Title, Priority: digestPriorityNames[todo.Priority]}
+		if todo.ProjectID != nil {
+			item.Project = projectNames[*todo.ProjectID]
+		}
+		if todo.Deadline != nil {
+			item.Deadline = todo.Deadline.In(loc).Format("01-02 15:04")
+		}
+		section.Items = append(section.Items, item)
+	}
+	return section, nil
+}
+
+// RunDigestSender 定期发送到达发送时间的摘要，直到 ctx 结束
+func (s *DigestService) RunDigestSender(ctx context.Context) {
+	ticker := time.NewTicker(DigestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := s.SendDueDigests(time.Now())
+		if err != nil {
+			log.Printf("Failed to send digests: %v", err)
+		} else if count > 0 {
+			log.Printf("Sent %d digest emails", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
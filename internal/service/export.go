@@ -0,0 +1,144 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// exportBatchSize 导出时每次从数据库读取的事项数量
+const exportBatchSize = 200
+
+// exportCSVHeader CSV 导出的表头，导入时按表头名称识别列
+var exportCSVHeader = []string{"uid", "title", "content", "status", "priority", "project", "deadline", "completed_at", "created_at"}
+
+// ExportTodos 将用户的全部待办事项按指定格式写入 w，分批读取数据库以支持大量事项
+func (s *TodoService) ExportTodos(userID int64, format string, w io.Writer) error {
+	switch format {
+	case model.TransferFormatJSON, model.TransferFormatCSV:
+	case model.TransferFormatMarkdown:
+		return s.exportMarkdown(userID, w)
+	default:
+		return errors.New("导出格式无效")
+	}
+
+	projectNames, err := s.projectNames(userID)
+	if err != nil {
+		return err
+	}
+
+	var write func(record *model.TodoExportRecord) error
+	var finish func() error
+	if format == model.TransferFormatCSV {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportCSVHeader); err != nil {
+			return err
+		}
+		write = func(record *model.TodoExportRecord) error {
+			return cw.Write([]string{
+				record.UID, record.Title, record.Content, record.Status, strconv.Itoa(record.Priority),
+				record.Project, record.Deadline, record.CompletedAt, record.CreatedAt,
+			})
+		}
+		finish = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	} else {
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+		first := true
+		write = func(record *model.TodoExportRecord) error {
+			data, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			_, err = w.Write(data)
+			return err
+		}
+		finish = func() error {
+			_, err := io.WriteString(w, "]")
+			return err
+		}
+	}
+
+	var todos []model.Todo
+	var writeErr error
+	result := s.db.Where("user_id = ?", userID).Order("id asc").
+		FindInBatches(&todos, exportBatchSize, func(tx *gorm.DB, batch int) error {
+			for i := range todos {
+				record := todoToExportRecord(&todos[i], projectNames)
+				if writeErr = write(&record); writeErr != nil {
+					return writeErr
+				}
+			}
+			return nil
+		})
+	if writeErr != nil {
+		return writeErr
+	}
+	if result.Error != nil {
+		return errors.New("查询失败")
+	}
+	return finish()
+}
+
+// projectNames 查询用户的清单名称
+func (s *TodoService) projectNames(userID int64) (map[int64]string, error) {
+	var projects []model.Project
+	if err := s.db.Where("user_id = ?", userID).Find(&projects).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	names := make(map[int64]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+	return names, nil
+}
+
+// todoToExportRecord 将待办事项转换为导出记录
+func todoToExportRecord(todo *model.Todo, projectNames map[int64]string) model.TodoExportRecord {
+	record := model.TodoExportRecord{
+		UID:       exportUID(todo),
+		Title:     todo.Title,
+		Content:   todo.Content,
+		Status:    "pending",
+		Priority:  todo.Priority,
+		CreatedAt: todo.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if todo.Status == 1 {
+		record.Status = "completed"
+	}
+	if todo.ProjectID != nil {
+		record.Project = projectNames[*todo.ProjectID]
+	}
+	if todo.Deadline != nil {
+		record.Deadline = todo.Deadline.UTC().Format(time.RFC3339)
+	}
+	if todo.CompletedAt != nil {
+		record.CompletedAt = todo.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	return record
+}
+
+// exportUID 事项的导出标识，导入的事项沿用来源中的标识
+func exportUID(todo *model.Todo) string {
+	if todo.ExternalID != "" {
+		return todo.ExternalID
+	}
+	return nativeUIDPrefix + strconv.FormatInt(todo.ID, 10)
+}
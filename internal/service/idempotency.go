@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 幂等键参数
+var (
+	IdempotencyKeyTTL           = 24 * time.Hour  // 幂等键的有效期
+	IdempotencyLockTimeout      = time.Minute     // 第一次请求超过该时间仍未完成时视为已中断，允许重试
+	IdempotencyPurgeInterval    = time.Hour       // 清理过期幂等键的间隔
+	MaxIdempotencyKeyLength     = 255             // 幂等键的最大长度
+	MaxIdempotencyResponseBytes = 4 * 1024 * 1024 // 超过该长度的响应不保存，重试时会重新执行
+)
+
+// IdempotencyReplayHeaders 保存并在重试时返回的响应头
+var IdempotencyReplayHeaders = []string{"ETag", "Location"}
+
+// IdempotencyService 幂等键服务
+type IdempotencyService struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyService 创建幂等键服务
+func NewIdempotencyService(db *gorm.DB) *IdempotencyService {
+	return &IdempotencyService{db: db}
+}
+
+// RequestFingerprint 计算请求的指纹，用于检查同一幂等键是否用于内容不同的请求
+func RequestFingerprint(method, uri string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + uri + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Begin 占用幂等键。第一次使用时返回 StatusCode 为 0 的新记录，调用方处理请求后需要调用 Complete 或 Release；
+// 已有完成的记录时返回该记录，调用方直接返回保存的响应
+func (s *IdempotencyService) Begin(userID int64, key, fingerprint string) (*model.IdempotencyRecord, error) {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return nil, errors.New("幂等键无效")
+	}
+
+	now := time.Now()
+	// 过期的幂等键视为不存在
+	if err := s.db.Where("user_id = ? AND idempotency_key = ? AND expires_at <= ?", userID, key, now).
+		Delete(&model.IdempotencyRecord{}).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	record := model.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(IdempotencyKeyTTL),
+	}
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return nil, errors.New("保存幂等键失败")
+	}
+	if result.RowsAffected == 1 {
+		return &record, nil
+	}
+
+	var existing model.IdempotencyRecord
+	if err := s.db.Where("user_id = ? AND idempotency_key = ?", userID, key).First(&existing).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, errors.New("幂等键已用于其他请求")
+	}
+	if existing.StatusCode > 0 {
+		return &existing, nil
+	}
+
+	// 第一次请求仍在处理；超时未完成时由本次请求接管
+	if now.Sub(existing.CreatedAt) < IdempotencyLockTimeout {
+		return nil, errors.New("相同幂等键的请求正在处理")
+	}
+	result = s.db.Model(&model.IdempotencyRecord{}).
+		Where("id = ? AND status_code = 0 AND created_at = ?", existing.ID, existing.CreatedAt).
+		Update("created_at", now)
+	if result.Error != nil {
+		return nil, errors.New("保存幂等键失败")
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("相同幂等键的请求正在处理")
+	}
+	existing.CreatedAt = now
+	return &existing, nil
+}
+
+// Complete 保存请求的响应和 IdempotencyReplayHeaders 中的响应头，之后使用相同幂等键的请求直接返回该响应
+func (s *IdempotencyService) Complete(record *model.IdempotencyRecord, statusCode int, contentType string, headers map[string]string, body []byte) error {
+	if len(body) > MaxIdempotencyResponseBytes {
+		return s.Release(record)
+	}
+
+	encoded, err := json.Marshal(headers)
+	if err != nil {
+		return errors.New("保存幂等键失败")
+	}
+	if err := s.db.Model(record).Updates(map[string]interface{}{
+		"status_code":  statusCode,
+		"content_type": contentType,
+		"headers":      string(encoded),
+		"response":     string(body),
+	}).Error; err != nil {
+		return errors.New("保存幂等键失败")
+	}
+	return nil
+}
+
+// ReplayHeaders 解析记录中保存的响应头
+func ReplayHeaders(record *model.IdempotencyRecord) map[string]string {
+	headers := make(map[string]string)
+	if record.Headers != "" {
+		if err := json.Unmarshal([]byte(record.Headers), &headers); err != nil {
+			log.Printf("Failed to decode idempotency headers: %v", err)
+		}
+	}
+	return headers
+}
+
+// Release 释放幂等键，请求失败后可以使用相同的键重试
+func (s *IdempotencyService) Release(record *model.IdempotencyRecord) error {
+	if err := s.db.Where("status_code = 0").Delete(record).Error; err != nil {
+		return errors.New("释放幂等键失败")
+	}
+	return nil
+}
+
+// PurgeExpired 删除过期的幂等键，返回删除的数量
+func (s *IdempotencyService) PurgeExpired(now time.Time) (int64, error) {
+	result := s.db.Where("expires_at <= ?", now).Delete(&model.IdempotencyRecord{})
+	if result.Error != nil {
+		return 0, errors.New("清理幂等键失败")
+	}
+	return result.RowsAffected, nil
+}
+
+// RunIdempotencyPurger 定期清理过期的幂等键，直到 ctx 结束
+func (s *IdempotencyService) RunIdempotencyPurger(ctx context.Context) {
+	ticker := time.NewTicker(IdempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := s.PurgeExpired(time.Now()); err != nil {
+			log.Printf("Failed to purge idempotency keys: %v", err)
+		} else if count > 0 {
+			log.Printf("Purged %d expired idempotency keys", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
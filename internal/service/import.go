@@ -0,0 +1,450 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"RemindGo/internal/importer"
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MaxImportRecords 单次导入最多的记录数
+var MaxImportRecords = 5000
+
+// nativeUIDPrefix 本系统创建的事项在导出时使用的标识前缀
+const nativeUIDPrefix = "remindgo-"
+
+// importRecord 待导入的记录及其所在行号
+type importRecord struct {
+	Line   int
+	Record model.TodoExportRecord
+}
+
+// importCandidate 校验通过、等待写入的事项
+type importCandidate struct {
+	Line    int
+	Todo    model.Todo
+	Project string
+}
+
+// ImportTodos 导入待办事项。JSON、CSV 和 Markdown 中任一记录校验失败时不写入任何数据；
+// 已存在的事项（按 uid 判断，没有 uid 时按标题、内容和截止时间判断）会被跳过
+// 其他格式按名称查找 importer 中注册的第三方来源，filename 为上传的文件名（可能为空）
+func (s *TodoService) ImportTodos(userID int64, format, filename string, data []byte, dryRun bool) (*model.ImportResult, error) {
+	defer s.changed(userID)
+
+	var records []importRecord
+	var err error
+	switch format {
+	case model.TransferFormatJSON:
+		records, err = parseImportJSON(data)
+	case model.TransferFormatCSV:
+		records, err = parseImportCSV(data)
+	case model.TransferFormatMarkdown:
+		records, err = parseImportMarkdown(data)
+	case model.TransferFormatICS:
+		return s.importICS(userID, data, dryRun)
+	default:
+		source, ok := importer.Lookup(format)
+		if !ok {
+			return nil, errors.New("导入格式无效")
+		}
+		return s.importFromSource(userID, source, filename, data, dryRun)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > MaxImportRecords {
+		return nil, errors.New("导入记录数超过上限")
+	}
+
+	result := &model.ImportResult{
+		DryRun: dryRun,
+		Total:  len(records),
+		Errors: []model.ImportError{},
+	}
+	candidates := make([]importCandidate, 0, len(records))
+	for _, record := range records {
+		candidate, errs := validateImportRecord(&record)
+		if len(errs) > 0 {
+			result.Errors = append(result.Errors, errs...)
+			continue
+		}
+		candidates = append(candidates, *candidate)
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	if err := s.saveImport(userID, candidates, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// saveImport 去重并写入校验通过的事项，试运行时只统计数量
+func (s *TodoService) saveImport(userID int64, candidates []importCandidate, result *model.ImportResult) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		total := len(candidates)
+		candidates, err := s.dropDuplicates(tx, userID, candidates)
+		if err != nil {
+			return err
+		}
+		result.Duplicates = total - len(candidates)
+		result.Created = len(candidates)
+
+		projectIDs, created, err := s.resolveImportProjects(tx, userID, candidates, result.DryRun)
+		if err != nil {
+			return err
+		}
+		result.ProjectsCreated = created
+		if result.DryRun {
+			return nil
+		}
+
+		for i := range candidates {
+			todo := &candidates[i].Todo
+			todo.UserID = userID
+			if candidates[i].Project != "" {
+				projectID := projectIDs[candidates[i].Project]
+				todo.ProjectID = &projectID
+			}
+			if err := tx.Create(todo).Error; err != nil {
+				return errors.New("导入失败")
+			}
+			if err := recordRevision(tx, nil, todo); err != nil {
+				return err
+			}
+			if err := recordActivity(tx, userID, todo, model.ActivityCreated, "import", diffTodo(nil, todo)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// importFromSource 使用第三方来源的适配器导入，无法导入的记录会被跳过并说明原因
+func (s *TodoService) importFromSource(userID int64, source importer.Source, filename string, data []byte, dryRun bool) (*model.ImportResult, error) {
+	parsed, err := source.Parse(filename, data)
+	if err != nil {
+		return nil, errors.New("导入文件格式错误: " + err.Error())
+	}
+	if parsed.Total > MaxImportRecords {
+		return nil, errors.New("导入记录数超过上限")
+	}
+
+	result := &model.ImportResult{
+		DryRun:   dryRun,
+		Total:    parsed.Total,
+		Errors:   []model.ImportError{},
+		Skipped:  parsed.Skipped,
+		Warnings: parsed.Warnings,
+	}
+	candidates := make([]importCandidate, len(parsed.Items))
+	for i, item := range parsed.Items {
+		if !validPriority(item.Todo.Priority) {
+			item.Todo.Priority = model.PriorityNone
+		}
+		if item.Todo.ExternalID == "" {
+			item.Todo.ExternalID = contentUID(&item.Todo)
+		}
+		candidates[i] = importCandidate{Line: item.Line, Todo: item.Todo, Project: item.Project}
+	}
+
+	if err := s.saveImport(userID, candidates, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseImportJSON 解析 JSON 数组，行号为每条记录起始位置所在的行
+func parseImportJSON(data []byte) ([]importRecord, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, errors.New("导入文件格式错误: 需要 JSON 数组")
+	}
+
+	var records []importRecord
+	for dec.More() {
+		line := lineAt(data, skipSpace(data, dec.InputOffset()))
+		var record model.TodoExportRecord
+		if err := dec.Decode(&record); err != nil {
+			return nil, errors.New("导入文件格式错误: 第" + strconv.Itoa(line) + "行附近 " + err.Error())
+		}
+		records = append(records, importRecord{Line: line, Record: record})
+	}
+	return records, nil
+}
+
+// parseImportCSV 解析带表头的 CSV，按表头名称识别列，未知列会被忽略
+func parseImportCSV(data []byte) ([]importRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("导入文件格式错误: 缺少表头")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("导入文件格式错误: 缺少 title 列")
+	}
+
+	var records []importRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("导入文件格式错误: " + err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+
+		raw := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		field := func(name string) string {
+			return strings.TrimSpace(raw(name))
+		}
+		record := model.TodoExportRecord{
+			UID:         field("uid"),
+			Title:       field("title"),
+			Content:     raw("content"), // 内容保留首尾的空白和换行
+			Status:      field("status"),
+			Project:     field("project"),
+			Deadline:    field("deadline"),
+			CompletedAt: field("completed_at"),
+			CreatedAt:   field("created_at"),
+		}
+		if value := field("priority"); value != "" {
+			priority, err := strconv.Atoi(value)
+			if err != nil {
+				priority = -1 // 交给校验报告错误
+			}
+			record.Priority = priority
+		}
+		records = append(records, importRecord{Line: line, Record: record})
+	}
+	return records, nil
+}
+
+// validateImportRecord 校验单条记录并转换为待写入的事项
+func validateImportRecord(record *importRecord) (*importCandidate, []model.ImportError) {
+	r := &record.Record
+	var errs []model.ImportError
+	fail := func(field, msg string) {
+		errs = append(errs, model.ImportError{Line: record.Line, Field: field, Msg: msg})
+	}
+
+	title := strings.TrimSpace(r.Title)
+	if title == "" {
+		fail("title", "标题不能为空")
+	} else if utf8.RuneCountInString(title) > 255 {
+		fail("title", "标题不能超过255个字符")
+	}
+	if utf8.RuneCountInString(r.Content) > 1000 {
+		fail("content", "内容不能超过1000个字符")
+	}
+	if utf8.RuneCountInString(r.Project) > 100 {
+		fail("project", "清单名称不能超过100个字符")
+	}
+	if len(r.UID) > 255 {
+		fail("uid", "uid 不能超过255个字符")
+	}
+	if !validPriority(r.Priority) {
+		fail("priority", "优先级无效")
+	}
+
+	todo := model.Todo{
+		Title:    title,
+		Content:  r.Content,
+		Priority: r.Priority,
+	}
+	switch strings.ToLower(r.Status) {
+	case "", "pending", "0":
+	case "completed", "1":
+		todo.Status = 1
+	default:
+		fail("status", "状态无效，应为 pending 或 completed")
+	}
+
+	parseTime := func(field, value string) *time.Time {
+		if value == "" {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			fail(field, "时间格式错误，请使用ISO 8601格式")
+			return nil
+		}
+		return &t
+	}
+	todo.Deadline = parseTime("deadline", r.Deadline)
+	todo.CompletedAt = parseTime("completed_at", r.CompletedAt)
+	if createdAt := parseTime("created_at", r.CreatedAt); createdAt != nil {
+		todo.CreatedAt = *createdAt
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	// 状态与完成时间保持一致
+	if todo.Status == 1 && todo.CompletedAt == nil {
+		now := time.Now()
+		todo.CompletedAt = &now
+	} else if todo.Status == 0 {
+		todo.CompletedAt = nil
+	}
+
+	todo.ExternalID = r.UID
+	if todo.ExternalID == "" {
+		todo.ExternalID = contentUID(&todo)
+	}
+	return &importCandidate{Line: record.Line, Todo: todo, Project: strings.TrimSpace(r.Project)}, nil
+}
+
+// contentUID 没有 uid 的记录按标题、内容和截止时间生成标识
+func contentUID(todo *model.Todo) string {
+	deadline := ""
+	if todo.Deadline != nil {
+		deadline = todo.Deadline.UTC().Format(time.RFC3339)
+	}
+	sum := sha256.Sum256([]byte(todo.Title + "\x00" + todo.Content + "\x00" + deadline))
+	return "sha256-" + hex.EncodeToString(sum[:16])
+}
+
+// dropDuplicates 去掉已存在的事项（包括回收站中的事项）以及文件内重复的记录
+func (s *TodoService) dropDuplicates(tx *gorm.DB, userID int64, candidates []importCandidate) ([]importCandidate, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	uids := make([]string, len(candidates))
+	var nativeIDs []int64
+	for i, candidate := range candidates {
+		uids[i] = candidate.Todo.ExternalID
+		if id, ok := nativeUID(candidate.Todo.ExternalID); ok {
+			nativeIDs = append(nativeIDs, id)
+		}
+	}
+
+	existing := make(map[string]bool)
+	var externalIDs []string
+	if err := tx.Unscoped().Model(&model.Todo{}).
+		Where("user_id = ? AND external_id IN ?", userID, uids).
+		Pluck("external_id", &externalIDs).Error; err != nil {
+		return nil, errors.New("导入失败")
+	}
+	for _, uid := range externalIDs {
+		existing[uid] = true
+	}
+	if len(nativeIDs) > 0 {
+		var ids []int64
+		if err := tx.Unscoped().Model(&model.Todo{}).
+			Where("user_id = ? AND id IN ?", userID, nativeIDs).
+			Pluck("id", &ids).Error; err != nil {
+			return nil, errors.New("导入失败")
+		}
+		for _, id := range ids {
+			existing[nativeUIDPrefix+strconv.FormatInt(id, 10)] = true
+		}
+	}
+
+	kept := candidates[:0]
+	for _, candidate := range candidates {
+		if existing[candidate.Todo.ExternalID] {
+			continue
+		}
+		existing[candidate.Todo.ExternalID] = true
+		kept = append(kept, candidate)
+	}
+	return kept, nil
+}
+
+// resolveImportProjects 按名称匹配清单，不存在的清单会被创建（试运行时只计数）
+func (s *TodoService) resolveImportProjects(tx *gorm.DB, userID int64, candidates []importCandidate, dryRun bool) (map[string]int64, int, error) {
+	var projects []model.Project
+	if err := tx.Where("user_id = ?", userID).Order("id asc").Find(&projects).Error; err != nil {
+		return nil, 0, errors.New("导入失败")
+	}
+	projectIDs := make(map[string]int64, len(projects))
+	maxOrder := -1
+	for _, project := range projects {
+		if _, ok := projectIDs[project.Name]; !ok {
+			projectIDs[project.Name] = project.ID
+		}
+		if project.SortOrder > maxOrder {
+			maxOrder = project.SortOrder
+		}
+	}
+
+	created := 0
+	for _, candidate := range candidates {
+		if candidate.Project == "" {
+			continue
+		}
+		if _, ok := projectIDs[candidate.Project]; ok {
+			continue
+		}
+		created++
+		if dryRun {
+			projectIDs[candidate.Project] = 0
+			continue
+		}
+		maxOrder++
+		project := model.Project{UserID: userID, Name: candidate.Project, SortOrder: maxOrder}
+		if err := tx.Create(&project).Error; err != nil {
+			return nil, 0, errors.New("导入失败")
+		}
+		projectIDs[project.Name] = project.ID
+	}
+	return projectIDs, created, nil
+}
+
+// nativeUID 解析本系统导出的标识
+func nativeUID(uid string) (int64, bool) {
+	if !strings.HasPrefix(uid, nativeUIDPrefix) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(uid, nativeUIDPrefix), 10, 64)
+	return id, err == nil
+}
+
+// lineAt 计算字节偏移所在的行号
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// skipSpace 跳过偏移处的空白和逗号，定位到下一个值的起始位置
+func skipSpace(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"RemindGo/internal/ical"
+	"RemindGo/internal/model"
+)
+
+// importICS 导入 iCalendar 文件中的 VTODO 和 VEVENT。
+// 无法导入的组件会被跳过并在结果中说明原因，不影响其余组件
+func (s *TodoService) importICS(userID int64, data []byte, dryRun bool) (*model.ImportResult, error) {
+	cal, err := ical.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("导入文件格式错误: " + err.Error())
+	}
+	if cal.Name != "VCALENDAR" {
+		return nil, errors.New("导入文件格式错误: 缺少 VCALENDAR")
+	}
+
+	result := &model.ImportResult{
+		DryRun: dryRun,
+		Errors: []model.ImportError{},
+	}
+	// 不带时区的浮动时间按用户设置的时区解释
+	loc, err := s.userLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	tz := ical.NewTimezones(cal, loc)
+	now := time.Now()
+
+	var candidates []importCandidate
+	for _, c := range cal.Components {
+		switch c.Name {
+		case "VTIMEZONE":
+			continue
+		case "VTODO", "VEVENT":
+		default:
+			result.Skipped = append(result.Skipped, model.ImportError{
+				Line: c.Line, Field: c.Name, Msg: "不支持的组件类型",
+			})
+			continue
+		}
+
+		result.Total++
+		if result.Total > MaxImportRecords {
+			return nil, errors.New("导入记录数超过上限")
+		}
+		candidate, reason, warnings := icsComponentToCandidate(c, tz, now)
+		result.Warnings = append(result.Warnings, warnings...)
+		if reason != "" {
+			result.Skipped = append(result.Skipped, model.ImportError{Line: c.Line, Field: c.Name, Msg: reason})
+			continue
+		}
+		candidates = append(candidates, *candidate)
+	}
+
+	if err := s.saveImport(userID, candidates, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// icsComponentToCandidate 将 VTODO 或 VEVENT 转换为待导入的事项，
+// 无法导入时返回跳过原因，warnings 记录导入时丢失的信息
+func icsComponentToCandidate(c *ical.Component, tz *ical.Timezones, now time.Time) (*importCandidate, string, []model.ImportError) {
+	var warnings []model.ImportError
+	warn := func(field, msg string) {
+		warnings = append(warnings, model.ImportError{Line: c.Line, Field: field, Msg: msg})
+	}
+
+	if c.Get("RECURRENCE-ID") != nil {
+		return nil, "重复事项的单次修改不单独导入", nil
+	}
+	status := strings.ToUpper(c.Text("STATUS"))
+	if status == "CANCELLED" {
+		return nil, "已取消", nil
+	}
+
+	title := strings.TrimSpace(c.Text("SUMMARY"))
+	if title == "" {
+		return nil, "缺少标题（SUMMARY）", nil
+	}
+	if utf8.RuneCountInString(title) > 255 {
+		title = string([]rune(title)[:255])
+		warn("SUMMARY", "标题超过255个字符，已截断")
+	}
+	content := c.Text("DESCRIPTION")
+	if utf8.RuneCountInString(content) > 1000 {
+		content = string([]rune(content)[:1000])
+		warn("DESCRIPTION", "内容超过1000个字符，已截断")
+	}
+
+	todo := model.Todo{
+		Title:    title,
+		Content:  content,
+		Priority: priorityFromICal(c.Text("PRIORITY")),
+	}
+
+	// 截止时间：VTODO 取 DUE，VEVENT 取 DTSTART
+	field := "DUE"
+	if c.Name == "VEVENT" {
+		field = "DTSTART"
+	}
+	if prop := c.Get(field); prop != nil {
+		deadline, allDay, err := tz.ParseTime(prop)
+		if err != nil {
+			return nil, err.Error(), warnings
+		}
+		// 全天事项截止到当天结束
+		if allDay {
+			deadline = deadline.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		if rule := c.Get("RRULE"); rule != nil {
+			recur, err := ical.ParseRecur(rule.Value, deadline.Location())
+			if err != nil {
+				warn("RRULE", err.Error()+"，仅导入首次时间")
+			} else {
+				deadline, _ = recur.Next(deadline, now)
+				warn("RRULE", "暂不支持重复事项，截止时间取下一次重复")
+			}
+		}
+		todo.Deadline = &deadline
+	} else if c.Get("RRULE") != nil {
+		warn("RRULE", "缺少开始时间，已忽略重复规则")
+	}
+
+	// 完成状态
+	completed := status == "COMPLETED" || c.Text("PERCENT-COMPLETE") == "100"
+	if prop := c.Get("COMPLETED"); prop != nil && c.Name == "VTODO" {
+		completedAt, _, err := tz.ParseTime(prop)
+		if err != nil {
+			warn("COMPLETED", err.Error())
+		} else {
+			todo.CompletedAt = &completedAt
+			completed = true
+		}
+	}
+	if completed {
+		todo.Status = 1
+		if todo.CompletedAt == nil {
+			todo.CompletedAt = &now
+		}
+	}
+
+	if prop := c.Get("CREATED"); prop != nil {
+		if createdAt, _, err := tz.ParseTime(prop); err == nil {
+			todo.CreatedAt = createdAt
+		}
+	}
+
+	// 第一个分类作为清单
+	var project string
+	if prop := c.Get("CATEGORIES"); prop != nil {
+		categories := ical.SplitText(prop.Value)
+		project = strings.TrimSpace(categories[0])
+		if utf8.RuneCountInString(project) > 100 {
+			project = string([]rune(project)[:100])
+		}
+		if len(categories) > 1 {
+			warn("CATEGORIES", "只使用第一个分类作为清单")
+		}
+	}
+
+	todo.ExternalID = c.Text("UID")
+	if len(todo.ExternalID) > 255 {
+		return nil, "UID 过长", warnings
+	}
+	if todo.ExternalID == "" {
+		todo.ExternalID = contentUID(&todo)
+	}
+	return &importCandidate{Line: c.Line, Todo: todo, Project: project}, "", warnings
+}
+
+// priorityFromICal 将 iCalendar 的 PRIORITY（1 最高，9 最低，0 未定义）转换为优先级
+func priorityFromICal(value string) int {
+	p, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return model.PriorityNone
+	}
+	switch {
+	case p == 1:
+		return model.PriorityUrgent
+	case p >= 2 && p <= 4:
+		return model.PriorityHigh
+	case p == 5:
+		return model.PriorityMedium
+	case p >= 6 && p <= 9:
+		return model.PriorityLow
+	}
+	return model.PriorityNone
+}
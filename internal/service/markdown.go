@@ -0,0 +1,270 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// Markdown 任务列表中的元数据，写在标题之后，如 `- [ ] 写周报 due:2024-05-01T10:00:00Z priority:2`
+const (
+	markdownDue      = "due:"
+	markdownPriority = "priority:"
+	markdownDone     = "done:"
+)
+
+var (
+	// markdownTaskPattern 匹配任务列表项，分组依次为缩进、勾选状态和标题
+	markdownTaskPattern = regexp.MustCompile(`^([ \t]*)[-*+] \[([ xX])\](?:[ \t]+(.*))?$`)
+	// markdownHeadingPattern 匹配 ATX 标题，分组依次为级别和标题文本
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	// markdownUIDPattern 匹配行尾记录事项标识的 HTML 注释
+	markdownUIDPattern = regexp.MustCompile(`[ \t]*<!--[ \t]*uid:[ \t]*(\S*?)[ \t]*-->[ \t]*$`)
+	// markdownMetaTokenPattern 匹配标题中形如元数据的词（可能已带有转义用的反斜杠），
+	// 导出时在前面加一个反斜杠，导入时去掉一个，使标题原样往返
+	markdownMetaTokenPattern = regexp.MustCompile(`(^|[ \t])(\\*(?:due|done|priority):)`)
+	// markdownEscapedTokenPattern 匹配标题中转义过的元数据词
+	markdownEscapedTokenPattern = regexp.MustCompile(`(^|[ \t])\\(\\*(?:due|done|priority):)`)
+)
+
+// exportMarkdown 导出为 GitHub 风格的任务列表，按清单分为二级标题，未归入清单的事项在最前面
+func (s *TodoService) exportMarkdown(userID int64, w io.Writer) error {
+	var projects []model.Project
+	if err := s.db.Where("user_id = ?", userID).Order("sort_order asc, id asc").Find(&projects).Error; err != nil {
+		return errors.New("查询失败")
+	}
+
+	bw := bufio.NewWriter(w)
+	first := true
+	writeGroup := func(heading string, query *gorm.DB) error {
+		var todos []model.Todo
+		var writeErr error
+		written := false
+		result := query.Order("id asc").FindInBatches(&todos, exportBatchSize, func(tx *gorm.DB, batch int) error {
+			for i := range todos {
+				if !written && heading != "" {
+					if !first {
+						bw.WriteString("\n")
+					}
+					bw.WriteString("## " + heading + "\n\n")
+				}
+				written = true
+				first = false
+				if _, writeErr = bw.WriteString(markdownTask(&todos[i])); writeErr != nil {
+					return writeErr
+				}
+			}
+			return nil
+		})
+		if writeErr != nil {
+			return writeErr
+		}
+		if result.Error != nil {
+			return errors.New("查询失败")
+		}
+		return nil
+	}
+
+	if err := writeGroup("", s.db.Where("user_id = ? AND project_id IS NULL", userID)); err != nil {
+		return err
+	}
+	for _, project := range projects {
+		heading := strings.TrimSpace(markdownTitleReplacer.Replace(project.Name))
+		if err := writeGroup(heading, s.db.Where("user_id = ? AND project_id = ?", userID, project.ID)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// markdownTitleReplacer 标题只能占一行
+var markdownTitleReplacer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// markdownTask 生成单个任务项，内容作为缩进的后续行
+func markdownTask(todo *model.Todo) string {
+	var b strings.Builder
+	if todo.Status == 1 {
+		b.WriteString("- [x] ")
+	} else {
+		b.WriteString("- [ ] ")
+	}
+	title := strings.TrimSpace(markdownTitleReplacer.Replace(todo.Title))
+	b.WriteString(markdownMetaTokenPattern.ReplaceAllString(title, `$1\$2`))
+	if todo.Deadline != nil {
+		b.WriteString(" " + markdownDue + todo.Deadline.UTC().Format(time.RFC3339))
+	}
+	if todo.Priority > 0 {
+		b.WriteString(" " + markdownPriority + strconv.Itoa(todo.Priority))
+	}
+	if todo.Status == 1 && todo.CompletedAt != nil {
+		b.WriteString(" " + markdownDone + todo.CompletedAt.UTC().Format(time.RFC3339))
+	}
+	b.WriteString(" <!-- uid:" + exportUID(todo) + " -->\n")
+
+	if todo.Content != "" {
+		content := strings.ReplaceAll(todo.Content, "\r\n", "\n")
+		for _, line := range strings.Split(content, "\n") {
+			if strings.TrimSpace(line) == "" {
+				b.WriteString("\n")
+				continue
+			}
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// parseImportMarkdown 解析 Markdown 中的任务列表项。
+// 二级标题作为后续事项的清单，一级标题清除清单；任务项之后缩进的行作为内容，
+// 嵌套的列表和代码块也归入内容；其余段落和普通列表会被忽略
+func parseImportMarkdown(data []byte) ([]importRecord, error) {
+	text := strings.ReplaceAll(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	var records []importRecord
+	project := ""
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		// 跳过代码块中的内容
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if m := markdownHeadingPattern.FindStringSubmatch(line); m != nil {
+			switch len(m[1]) {
+			case 1:
+				project = ""
+			case 2:
+				project = strings.TrimSpace(m[2])
+			}
+			continue
+		}
+
+		m := markdownTaskPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		record := markdownTaskRecord(m[3])
+		if m[2] != " " {
+			record.Status = "completed"
+		}
+		record.Project = project
+		taskLine := i + 1
+
+		// 收集缩进深于任务项的后续行作为内容，中间的空行只在后面还有内容时保留
+		indent := markdownIndent(m[1]) + 2
+		var content []string
+		blank := 0
+		for i+1 < len(lines) {
+			next := lines[i+1]
+			if strings.TrimSpace(next) == "" {
+				blank++
+				i++
+				continue
+			}
+			if markdownIndent(next) < indent {
+				break
+			}
+			for ; blank > 0; blank-- {
+				content = append(content, "")
+			}
+			content = append(content, markdownDedent(next, indent))
+			i++
+		}
+		record.Content = strings.Join(content, "\n")
+
+		records = append(records, importRecord{Line: taskLine, Record: record})
+	}
+	return records, nil
+}
+
+// markdownTaskRecord 从任务项的文本中解析标题和行尾的元数据
+func markdownTaskRecord(text string) model.TodoExportRecord {
+	var record model.TodoExportRecord
+	if m := markdownUIDPattern.FindStringSubmatchIndex(text); m != nil {
+		record.UID = text[m[2]:m[3]]
+		text = text[:m[0]]
+	}
+
+	text = strings.TrimSpace(text)
+	for text != "" {
+		start := strings.LastIndexAny(text, " \t") + 1
+		token := text[start:]
+		switch {
+		case strings.HasPrefix(token, markdownDue) && record.Deadline == "":
+			record.Deadline = markdownTime(strings.TrimPrefix(token, markdownDue))
+		case strings.HasPrefix(token, markdownDone) && record.CompletedAt == "":
+			record.CompletedAt = markdownTime(strings.TrimPrefix(token, markdownDone))
+		case strings.HasPrefix(token, markdownPriority) && record.Priority == 0:
+			priority, err := strconv.Atoi(strings.TrimPrefix(token, markdownPriority))
+			if err != nil {
+				priority = -1 // 交给校验报告错误
+			}
+			record.Priority = priority
+		default:
+			record.Title = markdownEscapedTokenPattern.ReplaceAllString(text, "$1$2")
+			return record
+		}
+		text = strings.TrimSpace(text[:start])
+	}
+	return record
+}
+
+// markdownTime 只有日期时截止到当天结束（UTC），其余格式交给校验
+func markdownTime(value string) string {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Add(24*time.Hour - time.Second).Format(time.RFC3339)
+	}
+	return value
+}
+
+// markdownIndent 计算行首缩进的宽度，制表符按4个空格计算
+func markdownIndent(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// markdownDedent 去掉行首指定宽度的缩进
+func markdownDedent(line string, indent int) string {
+	width := 0
+	for i, r := range line {
+		if width >= indent || (r != ' ' && r != '\t') {
+			return line[i:]
+		}
+		if r == ' ' {
+			width++
+		} else {
+			width += 4 - width%4
+		}
+	}
+	return ""
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+
+	"RemindGo/internal/cache"
+	"RemindGo/internal/model"
+	"RemindGo/internal/realtime"
+
+	"gorm.io/gorm"
+)
+
+// ProjectService 清单服务
+type ProjectService struct {
+	db     *gorm.DB
+	cache  *cache.Store     // 删除清单会修改事项，需要使事项缓存失效
+	broker *realtime.Broker // 同时通知实时连接
+}
+
+// NewProjectService 创建清单服务，broker 为 nil 时不推送变更
+func NewProjectService(db *gorm.DB, store *cache.Store, broker *realtime.Broker) *ProjectService {
+	return &ProjectService{db: db, cache: store, broker: broker}
+}
+
+// changed 在修改事项之后调用（通过 defer，在事务提交之后执行）：使缓存失效并通知实时连接
+func (s *ProjectService) changed(userID int64) {
+	s.cache.Invalidate(userID)
+	s.broker.Publish(userID)
+}
+
+// GetProjectList 获取清单列表
+func (s *ProjectService) GetProjectList(userID int64, params *model.ProjectQueryParams) ([]model.ProjectResponse, error) {
+	query := s.db.Where("user_id = ?", userID)
+	if !params.IncludeArchived {
+		query = query.Where("archived = ?", false)
+	}
+
+	var projects []model.Project
+	if err := query.Order("sort_order asc, id asc").Find(&projects).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	items := make([]model.ProjectResponse, len(projects))
+	for i, project := range projects {
+		items[i] = s.projectToResponse(&project)
+	}
+	return items, nil
+}
+
+// CreateProject 创建清单
+func (s *ProjectService) CreateProject(userID int64, req *model.CreateProjectRequest) (*model.Project, error) {
+	project := model.Project{
+		UserID: userID,
+		Name:   req.Name,
+		Color:  req.Color,
+	}
+
+	if req.SortOrder != nil {
+		project.SortOrder = *req.SortOrder
+	} else {
+		// 默认排在最后
+		var maxOrder *int
+		if err := s.db.Model(&model.Project{}).Where("user_id = ?", userID).
+			Select("MAX(sort_order)").Scan(&maxOrder).Error; err != nil {
+			return nil, errors.New("创建失败")
+		}
+		if maxOrder != nil {
+			project.SortOrder = *maxOrder + 1
+		}
+	}
+
+	if err := s.db.Create(&project).Error; err != nil {
+		return nil, errors.New("创建失败")
+	}
+	return &project, nil
+}
+
+// GetProjectByID 获取单个清单
+func (s *ProjectService) GetProjectByID(userID, projectID int64) (*model.Project, error) {
+	var project model.Project
+	if err := s.db.Where("id = ? AND user_id = ?", projectID, userID).First(&project).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("清单不存在")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return &project, nil
+}
+
+// UpdateProject 更新清单
+func (s *ProjectService) UpdateProject(userID, projectID int64, req *model.UpdateProjectRequest) (*model.Project, error) {
+	project, err := s.GetProjectByID(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Color != nil {
+		updates["color"] = *req.Color
+	}
+	if req.Archived != nil {
+		updates["archived"] = *req.Archived
+	}
+	if req.SortOrder != nil {
+		updates["sort_order"] = *req.SortOrder
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(project).Updates(updates).Error; err != nil {
+			return nil, errors.New("更新失败")
+		}
+		// 重新查询
+		s.db.Where("id = ? AND user_id = ?", projectID, userID).First(project)
+	}
+
+	return project, nil
+}
+
+// DeleteProject 删除清单，清单下的待办事项会被移出清单而不会被删除
+func (s *ProjectService) DeleteProject(userID, projectID int64) error {
+	defer s.changed(userID)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", projectID, userID).Delete(&model.Project{})
+		if result.Error != nil {
+			return errors.New("删除失败")
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("清单不存在")
+		}
+
+		// 回收站中的事项也一并移出清单
+		todos, err := findTodos(tx.Unscoped().Where("user_id = ? AND project_id = ?", userID, projectID))
+		if err != nil {
+			return errors.New("删除失败")
+		}
+		if len(todos) == 0 {
+			return nil
+		}
+		if err := tx.Unscoped().Model(&model.Todo{}).Where("id IN ?", todoIDs(todos)).
+			Updates(bumpVersion(map[string]interface{}{"project_id": nil})).Error; err != nil {
+			return errors.New("删除失败")
+		}
+
+		// 逐条记录动态，同步客户端和 Webhook 才能得知事项已移出清单
+		for i := range todos {
+			before := todos[i]
+			todos[i].ProjectID = nil
+			todos[i].Version++
+			if err := recordActivity(tx, userID, &todos[i], updateAction(&before, &todos[i]), "project.delete", diffTodo(&before, &todos[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// projectToResponse 将清单模型转换为响应格式
+func (s *ProjectService) projectToResponse(project *model.Project) model.ProjectResponse {
+	return model.ProjectResponse{
+		ID:        project.ID,
+		Name:      project.Name,
+		Color:     project.Color,
+		Archived:  project.Archived,
+		SortOrder: project.SortOrder,
+		CreatedAt: project.CreatedAt.Unix(),
+		UpdatedAt: project.UpdatedAt.Unix(),
+	}
+}
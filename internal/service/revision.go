@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MaxRevisionsPerTodo 每个待办事项最多保留的版本数，超出后删除最旧的版本，0 表示不限制，服务启动时可由 REMINDGO_MAX_REVISIONS_PER_TODO 覆盖
+var MaxRevisionsPerTodo = 50
+
+// GetRevisions 获取待办事项的版本列表，按版本号倒序
+func (s *TodoService) GetRevisions(userID, todoID int64) ([]model.TodoRevision, error) {
+	if _, err := s.findTodo(s.db, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	var revisions []model.TodoRevision
+	if err := s.db.Where("todo_id = ?", todoID).Order("rev desc").Find(&revisions).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	return revisions, nil
+}
+
+// GetRevision 获取待办事项的指定版本
+func (s *TodoService) GetRevision(userID, todoID int64, rev int) (*model.TodoRevision, error) {
+	if _, err := s.findTodo(s.db, userID, todoID); err != nil {
+		return nil, err
+	}
+	return s.findRevision(todoID, rev)
+}
+
+// DiffRevisions 比较两个版本，to 为 0 时与最新版本比较
+func (s *TodoService) DiffRevisions(userID, todoID int64, from, to int) (*model.RevisionDiffResponse, error) {
+	if _, err := s.findTodo(s.db, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	if to == 0 {
+		var latest model.TodoRevision
+		if err := s.db.Where("todo_id = ?", todoID).Order("rev desc").First(&latest).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.New("版本不存在")
+			}
+			return nil, errors.New("查询失败")
+		}
+		to = latest.Rev
+	}
+
+	fromRevision, err := s.findRevision(todoID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.findRevision(todoID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.RevisionDiffResponse{
+		From: from,
+		To:   to,
+		Diff: unifiedDiff(
+			fmt.Sprintf("rev/%d", from),
+			fmt.Sprintf("rev/%d", to),
+			revisionText(fromRevision),
+			revisionText(toRevision),
+		),
+	}, nil
+}
+
+// RestoreRevision 将待办事项的标题和内容恢复为指定版本，恢复本身会产生一个新版本
+func (s *TodoService) RestoreRevision(userID, todoID int64, rev int) (*model.Todo, error) {
+	revision, err := s.GetRevision(userID, todoID, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.UpdateTodo(userID, todoID, &model.UpdateTodoRequest{
+		Title:   &revision.Title,
+		Content: &revision.Content,
+	}, false, "")
+}
+
+// findRevision 查询指定版本
+func (s *TodoService) findRevision(todoID int64, rev int) (*model.TodoRevision, error) {
+	var revision model.TodoRevision
+	if err := s.db.Where("todo_id = ? AND rev = ?", todoID, rev).First(&revision).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("版本不存在")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return &revision, nil
+}
+
+// recordRevision 标题或内容发生变化时保存新版本，before 为空表示新建。
+// 对于尚无版本记录的旧事项，会先保存修改前的内容作为第一个版本
+func recordRevision(tx *gorm.DB, before, after *model.Todo) error {
+	if before != nil && before.Title == after.Title && before.Content == after.Content {
+		return nil
+	}
+
+	var latest int
+	if err := tx.Model(&model.TodoRevision{}).Where("todo_id = ?", after.ID).
+		Select("COALESCE(MAX(rev), 0)").Scan(&latest).Error; err != nil {
+		return errors.New("保存版本失败")
+	}
+
+	snapshots := []*model.Todo{after}
+	if latest == 0 && before != nil {
+		snapshots = []*model.Todo{before, after}
+	}
+	for _, snapshot := range snapshots {
+		latest++
+		revision := model.TodoRevision{
+			UserID:  after.UserID,
+			TodoID:  after.ID,
+			Rev:     latest,
+			Title:   snapshot.Title,
+			Content: snapshot.Content,
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return errors.New("保存版本失败")
+		}
+	}
+
+	// 删除超出上限的旧版本
+	if MaxRevisionsPerTodo > 0 && latest > MaxRevisionsPerTodo {
+		if err := tx.Where("todo_id = ? AND rev <= ?", after.ID, latest-MaxRevisionsPerTodo).
+			Delete(&model.TodoRevision{}).Error; err != nil {
+			return errors.New("保存版本失败")
+		}
+	}
+	return nil
+}
+
+// revisionText 将版本渲染为用于比较的文本，首行为标题，空行后为内容
+func revisionText(revision *model.TodoRevision) string {
+	return revision.Title + "\n\n" + revision.Content
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MaxStatsRangeDays 时间序列统计最多覆盖的天数
+var MaxStatsRangeDays = 731
+
+// statsDateLayout 统计中日期的格式
+const statsDateLayout = "2006-01-02"
+
+// GetStatsTimeseries 按天、周或月统计新建和完成的事项数，以及完成耗时、按时完成率、
+// 连续完成天数和每日热力图，日期按用户时区（或 tz 参数指定的时区）划分
+func (s *TodoService) GetStatsTimeseries(userID int64, params *model.StatsTimeseriesParams) (*model.StatsTimeseries, error) {
+	loc, err := s.statsLocation(userID, params.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := params.Bucket
+	if bucket == "" {
+		bucket = model.StatsBucketDay
+	}
+	from, to, err := statsRange(bucket, params.From, params.To, time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+	start := bucketStart(bucket, from)
+	end := to.AddDate(0, 0, 1)
+	if days := int(end.Sub(start).Hours()/24 + 0.5); days > MaxStatsRangeDays {
+		return nil, errors.New("统计范围过大")
+	}
+
+	scope := func() *gorm.DB {
+		query := s.db.Model(&model.Todo{}).Where("user_id = ?", userID)
+		if params.ProjectID != nil {
+			query = query.Where("project_id = ?", *params.ProjectID)
+		}
+		return query
+	}
+
+	var createdTimes []time.Time
+	if err := scope().Where("created_at >= ? AND created_at < ?", start, end).
+		Pluck("created_at", &createdTimes).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	var completedTodos []model.Todo
+	if err := scope().Select("id", "created_at", "completed_at", "deadline").
+		Where("status = ? AND completed_at >= ? AND completed_at < ?", 1, start, end).
+		Find(&completedTodos).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	var completionTimes []time.Time
+	if err := scope().Where("status = ? AND completed_at IS NOT NULL", 1).
+		Pluck("completed_at", &completionTimes).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	result := &model.StatsTimeseries{
+		Bucket:   bucket,
+		From:     start.Format(statsDateLayout),
+		To:       to.Format(statsDateLayout),
+		TimeZone: loc.String(),
+		Series:   []model.StatsBucket{},
+		Heatmap:  []model.StatsHeatmapDay{},
+	}
+
+	// 时间段和热力图的下标
+	bucketIndex := make(map[string]int)
+	for t := start; t.Before(end); t = nextBucket(bucket, t) {
+		bucketIndex[t.Format(statsDateLayout)] = len(result.Series)
+		result.Series = append(result.Series, model.StatsBucket{Start: t.Format(statsDateLayout)})
+	}
+	dayIndex := make(map[string]int)
+	for t := start; t.Before(end); t = t.AddDate(0, 0, 1) {
+		dayIndex[t.Format(statsDateLayout)] = len(result.Heatmap)
+		result.Heatmap = append(result.Heatmap, model.StatsHeatmapDay{Date: t.Format(statsDateLayout)})
+	}
+	bucketOf := func(t time.Time) (int, bool) {
+		i, ok := bucketIndex[bucketStart(bucket, localDate(t, loc)).Format(statsDateLayout)]
+		return i, ok
+	}
+
+	for _, createdAt := range createdTimes {
+		if i, ok := bucketOf(createdAt); ok {
+			result.Series[i].Created++
+		}
+	}
+
+	var totalDuration time.Duration
+	for _, todo := range completedTodos {
+		completedAt := *todo.CompletedAt
+		if i, ok := bucketOf(completedAt); ok {
+			result.Series[i].Completed++
+		}
+		if i, ok := dayIndex[localDate(completedAt, loc).Format(statsDateLayout)]; ok {
+			result.Heatmap[i].Completed++
+		}
+		if completedAt.After(todo.CreatedAt) {
+			totalDuration += completedAt.Sub(todo.CreatedAt)
+		}
+		switch {
+		case todo.Deadline == nil:
+			result.NoDeadline++
+		case completedAt.After(*todo.Deadline):
+			result.Late++
+		default:
+			result.OnTime++
+		}
+	}
+	if len(completedTodos) > 0 {
+		result.AvgCompletionSeconds = totalDuration.Seconds() / float64(len(completedTodos))
+	}
+
+	result.CurrentStreak, result.LongestStreak = completionStreaks(completionTimes, loc, time.Now())
+	return result, nil
+}
+
+// statsLocation 统计使用的时区：优先使用参数，否则使用用户设置的时区
+func (s *TodoService) statsLocation(userID int64, name string) (*time.Location, error) {
+	if name == "" {
+		return s.userLocation(userID)
+	}
+	return LoadTimeZone(name)
+}
+
+// userLocation 用户设置的时区，未设置时为 UTC
+func (s *TodoService) userLocation(userID int64) (*time.Location, error) {
+	var user model.User
+	if err := s.db.Select("id", "time_zone").First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("用户不存在")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return LoadTimeZone(user.TimeZone)
+}
+
+// statsRange 解析统计的起止日期（当天零点），未指定时按粒度取默认范围
+func statsRange(bucket, fromValue, toValue string, now time.Time) (time.Time, time.Time, error) {
+	loc := now.Location()
+	to := localDate(now, loc)
+	if toValue != "" {
+		t, err := time.ParseInLocation(statsDateLayout, toValue, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("日期格式错误，请使用 YYYY-MM-DD")
+		}
+		to = t
+	}
+
+	var from time.Time
+	switch bucket {
+	case model.StatsBucketDay:
+		from = to.AddDate(0, 0, -29)
+	case model.StatsBucketWeek:
+		from = to.AddDate(0, 0, -7*11)
+	case model.StatsBucketMonth:
+		from = to.AddDate(0, -11, 0)
+	default:
+		return time.Time{}, time.Time{}, errors.New("统计粒度无效")
+	}
+	if fromValue != "" {
+		t, err := time.ParseInLocation(statsDateLayout, fromValue, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("日期格式错误，请使用 YYYY-MM-DD")
+		}
+		from = t
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("开始日期不能晚于结束日期")
+	}
+	return from, to, nil
+}
+
+// localDate 时间在指定时区中所在日期的零点
+func localDate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// bucketStart 日期所在时间段的第一天
+func bucketStart(bucket string, day time.Time) time.Time {
+	switch bucket {
+	case model.StatsBucketWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // 周一为0
+		return day.AddDate(0, 0, -offset)
+	case model.StatsBucketMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+// nextBucket 下一个时间段的第一天
+func nextBucket(bucket string, start time.Time) time.Time {
+	switch bucket {
+	case model.StatsBucketWeek:
+		return start.AddDate(0, 0, 7)
+	case model.StatsBucketMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// completionStreaks 计算当前和最长的连续完成天数
+func completionStreaks(completions []time.Time, loc *time.Location, now time.Time) (current, longest int) {
+	// 以 UTC 零点表示本地日期，避免夏令时影响按天加减
+	civil := func(t time.Time) time.Time {
+		t = t.In(loc)
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	days := make(map[time.Time]bool, len(completions))
+	for _, t := range completions {
+		days[civil(t)] = true
+	}
+
+	for day := range days {
+		// 只从连续区间的第一天开始向后计数
+		if days[day.AddDate(0, 0, -1)] {
+			continue
+		}
+		length := 1
+		for days[day.AddDate(0, 0, length)] {
+			length++
+		}
+		if length > longest {
+			longest = length
+		}
+	}
+
+	day := civil(now)
+	if !days[day] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for days[day] {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+
+	"RemindGo/internal/model"
+)
+
+// StreamBacklogLimit 断线重连时最多补发的变更数，超过时推送 reset 事件，客户端需要重新拉取全部数据
+var StreamBacklogLimit = 500
+
+// maxChangesPageSize 每次查询最多返回的变更数
+const maxChangesPageSize = 500
+
+// SubscribeChanges 订阅用户的变更通知，返回的通道在有新变更时可读，使用完毕后必须调用返回的函数。
+// 通知只表示可能有新变更，需要通过 GetChanges 查询
+func (s *TodoService) SubscribeChanges(userID int64) (<-chan struct{}, func()) {
+	if s.broker == nil {
+		return nil, func() {}
+	}
+	return s.broker.Subscribe(userID)
+}
+
+// LatestChangeID 用户最新一条变更的ID，没有变更时为 0
+func (s *TodoService) LatestChangeID(userID int64) (int64, error) {
+	var id int64
+	if err := s.db.Model(&model.TodoActivity{}).Where("user_id = ?", userID).
+		Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, errors.New("查询失败")
+	}
+	return id, nil
+}
+
+// ResumeCursor 确定推送的起点。lastEventID 为 0 时从最新的变更之后开始；
+// 断线期间的变更超过 StreamBacklogLimit 或 lastEventID 无效时从最新的变更之后开始，并返回 reset 为 true
+func (s *TodoService) ResumeCursor(userID, lastEventID int64) (int64, bool, error) {
+	latest, err := s.LatestChangeID(userID)
+	if err != nil {
+		return 0, false, err
+	}
+	if lastEventID <= 0 {
+		return latest, false, nil
+	}
+	if lastEventID > latest {
+		return latest, true, nil
+	}
+
+	var backlog int64
+	if err := s.db.Model(&model.TodoActivity{}).Where("user_id = ? AND id > ?", userID, lastEventID).
+		Count(&backlog).Error; err != nil {
+		return 0, false, errors.New("查询失败")
+	}
+	if backlog > int64(StreamBacklogLimit) {
+		return latest, true, nil
+	}
+	return lastEventID, false, nil
+}
+
+// GetChanges 查询 afterID 之后的变更，按ID升序，最多返回 limit 条
+func (s *TodoService) GetChanges(userID, afterID int64, limit int) ([]model.ChangeEvent, error) {
+	if limit < 1 || limit > maxChangesPageSize {
+		limit = maxChangesPageSize
+	}
+
+	var activities []model.TodoActivity
+	if err := s.db.Where("user_id = ? AND id > ?", userID, afterID).
+		Order("id asc").Limit(limit).Find(&activities).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	if len(activities) == 0 {
+		return []model.ChangeEvent{}, nil
+	}
+
+	// 附带事项的当前状态，回收站中的事项也需要返回
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, activity := range activities {
+		if !seen[activity.TodoID] {
+			seen[activity.TodoID] = true
+			ids = append(ids, activity.TodoID)
+		}
+	}
+	var todos []model.Todo
+	if err := s.withDetails(s.db.Unscoped().Where("user_id = ? AND id IN ?", userID, ids)).
+		Find(&todos).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	todoByID := make(map[int64]*model.TodoResponse, len(todos))
+	for i := range todos {
+		resp := s.todoToResponse(&todos[i])
+		todoByID[todos[i].ID] = &resp
+	}
+
+	events := make([]model.ChangeEvent, len(activities))
+	for i, activity := range activities {
+		event := model.ChangeEvent{
+			ID:        activity.ID,
+			Type:      changeType(activity.Action),
+			TodoID:    activity.TodoID,
+			Action:    activity.Action,
+			Source:    activity.Source,
+			ActorID:   activity.ActorID,
+			CreatedAt: activity.CreatedAt.Unix(),
+		}
+		if activity.Changes != "" {
+			json.Unmarshal([]byte(activity.Changes), &event.Changes)
+		}
+		if activity.Action != model.ActivityPurged {
+			event.Todo = todoByID[activity.TodoID]
+		}
+		events[i] = event
+	}
+	return events, nil
+}
+
+// changeType 将动态类型映射为推送的事件类型
+func changeType(action string) string {
+	switch action {
+	case model.ActivityCreated:
+		return model.ChangeTodoCreated
+	case model.ActivityCompleted, model.ActivityReopened:
+		return model.ChangeTodoToggled
+	case model.ActivityDeleted, model.ActivityPurged:
+		return model.ChangeTodoDeleted
+	case model.ActivityRestored:
+		return model.ChangeTodoRestored
+	}
+	return model.ChangeTodoUpdated
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// 同步每次处理的变更数或事项数
+const (
+	defaultSyncLimit = 500
+	maxSyncLimit     = 1000
+)
+
+// Sync 返回令牌之后的事项变更。令牌为空时全量同步，按ID分页返回全部事项；
+// 令牌为同步序号时返回之后新建、修改的事项和已删除事项的墓碑
+func (s *TodoService) Sync(userID int64, params *model.SyncParams) (*model.SyncResponse, error) {
+	if params.Limit < 1 {
+		params.Limit = defaultSyncLimit
+	}
+	if params.Limit > maxSyncLimit {
+		params.Limit = maxSyncLimit
+	}
+
+	current, err := s.syncSeq(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 全量同步的令牌为 "序号.最后一个事项ID"，表示全量同步尚未完成
+	since, afterID, full, err := parseSyncToken(params.Since)
+	if err != nil {
+		return nil, err
+	}
+	if since > current {
+		return nil, errors.New("同步令牌无效")
+	}
+	if full {
+		if params.Since == "" {
+			since = current
+		}
+		return s.fullSync(userID, since, afterID, params.Limit)
+	}
+	return s.deltaSync(userID, since, params.Limit)
+}
+
+// fullSync 按ID分页返回全部事项，最后一页返回开始全量同步时的序号，
+// 同步期间发生的修改会在之后的增量同步中再次返回
+func (s *TodoService) fullSync(userID, seq, afterID int64, limit int) (*model.SyncResponse, error) {
+	var todos []model.Todo
+	if err := s.withDetails(s.db.Where("user_id = ? AND id > ?", userID, afterID)).
+		Order("id asc").Limit(limit + 1).Find(&todos).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	resp := &model.SyncResponse{
+		Created:   []model.TodoResponse{},
+		Updated:   []model.TodoResponse{},
+		Deleted:   []model.SyncTombstone{},
+		SyncToken: strconv.FormatInt(seq, 10),
+		Full:      true,
+	}
+	if len(todos) > limit {
+		todos = todos[:limit]
+		resp.HasMore = true
+		resp.SyncToken += "." + strconv.FormatInt(todos[limit-1].ID, 10)
+	}
+	for i := range todos {
+		resp.Created = append(resp.Created, s.todoToResponse(&todos[i]))
+	}
+	return resp, nil
+}
+
+// deltaSync 返回序号 since 之后有动态的事项，同一事项只返回当前状态
+func (s *TodoService) deltaSync(userID, since int64, limit int) (*model.SyncResponse, error) {
+	var activities []model.TodoActivity
+	if err := s.db.Where("user_id = ? AND seq > ?", userID, since).
+		Order("seq asc").Limit(limit + 1).Find(&activities).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	resp := &model.SyncResponse{
+		Created:   []model.TodoResponse{},
+		Updated:   []model.TodoResponse{},
+		Deleted:   []model.SyncTombstone{},
+		SyncToken: strconv.FormatInt(since, 10),
+	}
+	if len(activities) > limit {
+		activities = activities[:limit]
+		resp.HasMore = true
+	}
+	if len(activities) == 0 {
+		return resp, nil
+	}
+	resp.SyncToken = strconv.FormatInt(activities[len(activities)-1].Seq, 10)
+
+	var ids []int64
+	created := make(map[int64]bool)
+	last := make(map[int64]*model.TodoActivity)
+	for i := range activities {
+		activity := &activities[i]
+		if last[activity.TodoID] == nil {
+			ids = append(ids, activity.TodoID)
+		}
+		last[activity.TodoID] = activity
+		if activity.Action == model.ActivityCreated {
+			created[activity.TodoID] = true
+		}
+	}
+
+	// 回收站中的事项也需要查询，以返回删除时间
+	var todos []model.Todo
+	if err := s.withDetails(s.db.Unscoped().Where("user_id = ? AND id IN ?", userID, ids)).
+		Find(&todos).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	todoByID := make(map[int64]*model.Todo, len(todos))
+	for i := range todos {
+		todoByID[todos[i].ID] = &todos[i]
+	}
+
+	for _, id := range ids {
+		todo := todoByID[id]
+		switch {
+		case todo == nil:
+			// 已永久删除，删除时间取最后一条动态的时间
+			resp.Deleted = append(resp.Deleted, model.SyncTombstone{
+				ID:        id,
+				Purged:    true,
+				DeletedAt: last[id].CreatedAt.Unix(),
+			})
+		case todo.DeletedAt.Valid:
+			resp.Deleted = append(resp.Deleted, model.SyncTombstone{
+				ID:        id,
+				DeletedAt: todo.DeletedAt.Time.Unix(),
+			})
+		case created[id]:
+			resp.Created = append(resp.Created, s.todoToResponse(todo))
+		default:
+			resp.Updated = append(resp.Updated, s.todoToResponse(todo))
+		}
+	}
+	return resp, nil
+}
+
+// syncSeq 用户当前的同步序号
+func (s *TodoService) syncSeq(userID int64) (int64, error) {
+	var user model.User
+	if err := s.db.Select("sync_seq").First(&user, userID).Error; err != nil {
+		return 0, errors.New("查询失败")
+	}
+	return user.SyncSeq, nil
+}
+
+// parseSyncToken 解析同步令牌，full 为 true 表示全量同步，afterID 为已返回的最后一个事项ID
+func parseSyncToken(token string) (seq, afterID int64, full bool, err error) {
+	if token == "" {
+		return 0, 0, true, nil
+	}
+
+	seqPart, idPart, full := strings.Cut(token, ".")
+	seq, err = strconv.ParseInt(seqPart, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, 0, false, errors.New("同步令牌无效")
+	}
+	if full {
+		afterID, err = strconv.ParseInt(idPart, 10, 64)
+		if err != nil || afterID < 0 {
+			return 0, 0, false, errors.New("同步令牌无效")
+		}
+	}
+	return seq, afterID, full, nil
+}
+
+// nextSyncSeq 在事务中将用户的同步序号加一并返回新的序号。
+// 更新会锁定用户行直到事务提交，因此同一用户的序号顺序与提交顺序一致
+func nextSyncSeq(tx *gorm.DB, userID int64) (int64, error) {
+	if err := tx.Model(&model.User{}).Where("id = ?", userID).
+		UpdateColumn("sync_seq", gorm.Expr("sync_seq + 1")).Error; err != nil {
+		return 0, errors.New("记录动态失败")
+	}
+
+	var user model.User
+	if err := tx.Select("sync_seq").First(&user, userID).Error; err != nil {
+		return 0, errors.New("记录动态失败")
+	}
+	return user.SyncSeq, nil
+}
@@ -1,25 +1,52 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 
+	"RemindGo/internal/cache"
 	"RemindGo/internal/model"
+	"RemindGo/internal/realtime"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 缓存的有效期。统计和列表与当前时间有关（逾期、紧急度），有效期较短
+var (
+	TodoCacheTTL  = 5 * time.Minute
+	ListCacheTTL  = time.Minute
+	StatsCacheTTL = 30 * time.Second
 )
 
 // TodoService 待办事项服务
 type TodoService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	cache  *cache.Store     // 为 nil 时不缓存
+	broker *realtime.Broker // 为 nil 时不推送变更
+}
+
+// NewTodoService 创建待办事项服务，store 为 nil 时不使用缓存，broker 为 nil 时不推送变更
+func NewTodoService(db *gorm.DB, store *cache.Store, broker *realtime.Broker) *TodoService {
+	return &TodoService{db: db, cache: store, broker: broker}
 }
 
-// NewTodoService 创建待办事项服务
-func NewTodoService(db *gorm.DB) *TodoService {
+// withDB 返回使用指定数据库连接（通常是事务）的服务副本。
+// 副本不读写缓存也不推送变更，由外层方法在事务提交后处理
+func (s *TodoService) withDB(db *gorm.DB) *TodoService {
 	return &TodoService{db: db}
 }
 
+// changed 在修改之后调用（通常通过 defer，在事务提交之后执行）：使缓存失效并通知实时连接
+func (s *TodoService) changed(userID int64) {
+	s.cache.Invalidate(userID)
+	s.broker.Publish(userID)
+}
+
 // GetTodoList 获取待办事项列表
 func (s *TodoService) GetTodoList(userID int64, params *model.TodoQueryParams) (*model.TodoListResponse, error) {
 	// 设置默认值
@@ -42,19 +69,31 @@ func (s *TodoService) GetTodoList(userID int64, params *model.TodoQueryParams) (
 		params.SortOrder = "desc"
 	}
 
-	// 构建查询
-	query := s.db.Model(&model.Todo{}).Where("user_id = ?", userID)
-
-	// 状态过滤
-	if params.Status == "pending" {
-		query = query.Where("status = ?", 0)
-	} else if params.Status == "completed" {
-		query = query.Where("status = ?", 1)
+	// 只缓存第一页
+	if params.Page > 1 {
+		return s.queryTodoList(userID, params)
+	}
+	key, err := json.Marshal(params)
+	if err != nil {
+		return s.queryTodoList(userID, params)
 	}
+	return cache.Fetch(s.cache, userID, "list", string(key), ListCacheTTL, func() (*model.TodoListResponse, error) {
+		return s.queryTodoList(userID, params)
+	})
+}
 
-	// 关键词搜索
-	if params.Keyword != "" {
-		query = query.Where("title LIKE ? OR content LIKE ?", "%"+params.Keyword+"%", "%"+params.Keyword+"%")
+// queryTodoList 查询待办事项列表，params 已设置默认值
+func (s *TodoService) queryTodoList(userID int64, params *model.TodoQueryParams) (*model.TodoListResponse, error) {
+	// 构建查询
+	query, err := s.applyFilter(s.db.Model(&model.Todo{}).Where("user_id = ?", userID), &model.TodoFilter{
+		Status:    params.Status,
+		Keyword:   params.Keyword,
+		ProjectID: params.ProjectID,
+		Priority:  params.Priority,
+		Blocked:   params.Blocked,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// 统计总数
@@ -64,13 +103,12 @@ func (s *TodoService) GetTodoList(userID int64, params *model.TodoQueryParams) (
 	}
 
 	// 排序
-	orderClause := params.SortBy + " " + params.SortOrder
-	query = query.Order(orderClause)
+	query = s.applySort(query, params.SortBy, params.SortOrder)
 
 	// 分页
 	offset := (params.Page - 1) * params.PageSize
 	var todos []model.Todo
-	if err := query.Offset(offset).Limit(params.PageSize).Find(&todos).Error; err != nil {
+	if err := s.withDetails(query).Offset(offset).Limit(params.PageSize).Find(&todos).Error; err != nil {
 		return nil, errors.New("查询失败")
 	}
 
@@ -94,11 +132,21 @@ func (s *TodoService) GetTodoList(userID int64, params *model.TodoQueryParams) (
 
 // CreateTodo 创建待办事项
 func (s *TodoService) CreateTodo(userID int64, req *model.CreateTodoRequest) (*model.Todo, error) {
+	defer s.changed(userID)
+
 	todo := model.Todo{
-		UserID:  userID,
-		Title:   req.Title,
-		Content: req.Content,
-		Status:  0, // 默认待办
+		UserID:   userID,
+		Title:    req.Title,
+		Content:  req.Content,
+		Status:   0, // 默认待办
+		Priority: req.Priority,
+		Version:  1,
+
+		ChecklistAutoComplete: req.ChecklistAutoComplete,
+	}
+
+	if !validPriority(req.Priority) {
+		return nil, errors.New("优先级无效")
 	}
 
 	// 解析截止时间
@@ -110,8 +158,25 @@ func (s *TodoService) CreateTodo(userID int64, req *model.CreateTodoRequest) (*m
 		todo.Deadline = &deadline
 	}
 
-	if err := s.db.Create(&todo).Error; err != nil {
-		return nil, errors.New("创建失败")
+	// 校验所属清单
+	if req.ProjectID != nil && *req.ProjectID > 0 {
+		if err := s.checkProject(userID, *req.ProjectID); err != nil {
+			return nil, err
+		}
+		todo.ProjectID = req.ProjectID
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&todo).Error; err != nil {
+			return errors.New("创建失败")
+		}
+		if err := recordRevision(tx, nil, &todo); err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, &todo, model.ActivityCreated, "", diffTodo(nil, &todo))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &todo, nil
@@ -119,8 +184,15 @@ func (s *TodoService) CreateTodo(userID int64, req *model.CreateTodoRequest) (*m
 
 // GetTodoByID 获取单个待办事项
 func (s *TodoService) GetTodoByID(userID, todoID int64) (*model.Todo, error) {
+	return cache.Fetch(s.cache, userID, "todo", strconv.FormatInt(todoID, 10), TodoCacheTTL, func() (*model.Todo, error) {
+		return s.loadTodo(userID, todoID)
+	})
+}
+
+// loadTodo 从数据库读取待办事项及其关联数据，修改后返回最新数据时使用
+func (s *TodoService) loadTodo(userID, todoID int64) (*model.Todo, error) {
 	var todo model.Todo
-	if err := s.db.Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error; err != nil {
+	if err := s.withDetails(s.db).Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("待办事项不存在")
 		}
@@ -129,8 +201,11 @@ func (s *TodoService) GetTodoByID(userID, todoID int64) (*model.Todo, error) {
 	return &todo, nil
 }
 
-// UpdateTodo 更新待办事项
-func (s *TodoService) UpdateTodo(userID, todoID int64, req *model.UpdateTodoRequest) (*model.Todo, error) {
+// UpdateTodo 更新待办事项，force 为 true 时允许完成仍有未完成前置事项的事项，
+// ifMatch 不为空时只在事项的 ETag 与其匹配时更新
+func (s *TodoService) UpdateTodo(userID, todoID int64, req *model.UpdateTodoRequest, force bool, ifMatch string) (*model.Todo, error) {
+	defer s.changed(userID)
+
 	var todo model.Todo
 	if err := s.db.Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -138,6 +213,9 @@ func (s *TodoService) UpdateTodo(userID, todoID int64, req *model.UpdateTodoRequ
 		}
 		return nil, errors.New("查询失败")
 	}
+	if err := checkIfMatch(&todo, ifMatch); err != nil {
+		return nil, err
+	}
 
 	// 更新字段
 	updates := make(map[string]interface{})
@@ -157,145 +235,538 @@ func (s *TodoService) UpdateTodo(userID, todoID int64, req *model.UpdateTodoRequ
 		}
 	}
 	if req.Deadline != nil {
-		if *req.Deadline == "" {
-			updates["deadline"] = nil
+		deadline, err := deadlineUpdate(*req.Deadline)
+		if err != nil {
+			return nil, err
+		}
+		updates["deadline"] = deadline
+	}
+	if req.ProjectID != nil {
+		if *req.ProjectID <= 0 {
+			updates["project_id"] = nil
 		} else {
-			deadline, err := time.Parse(time.RFC3339, *req.Deadline)
-			if err != nil {
-				return nil, errors.New("截止时间格式错误，请使用ISO 8601格式")
+			if err := s.checkProject(userID, *req.ProjectID); err != nil {
+				return nil, err
 			}
-			updates["deadline"] = &deadline
+			updates["project_id"] = *req.ProjectID
 		}
 	}
+	if req.Priority != nil {
+		if !validPriority(*req.Priority) {
+			return nil, errors.New("优先级无效")
+		}
+		updates["priority"] = *req.Priority
+	}
+	if req.ChecklistAutoComplete != nil {
+		updates["checklist_auto_complete"] = *req.ChecklistAutoComplete
+	}
 
 	if len(updates) > 0 {
-		if err := s.db.Model(&todo).Updates(updates).Error; err != nil {
-			return nil, errors.New("更新失败")
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			before := todo
+			// 完成前检查前置事项，与 ToggleTodo 相同
+			if req.Status != nil && *req.Status == 1 && todo.Status == 0 && !force {
+				if err := lockDependencies(tx, userID); err != nil {
+					return err
+				}
+				blocked, err := s.hasPendingBlockers(tx, todoID)
+				if err != nil {
+					return err
+				}
+				if blocked {
+					return errors.New("存在未完成的前置事项")
+				}
+			}
+			if err := updateTodoRow(tx, &todo, updates, ifMatch); err != nil {
+				return err
+			}
+			if err := recordRevision(tx, &before, &todo); err != nil {
+				return err
+			}
+			if err := recordActivity(tx, userID, &todo, updateAction(&before, &todo), "", diffTodo(&before, &todo)); err != nil {
+				return err
+			}
+			// 开启自动完成时按当前检查项同步状态，同时指定了状态时以请求为准
+			if !before.ChecklistAutoComplete && req.ChecklistAutoComplete != nil && *req.ChecklistAutoComplete && req.Status == nil {
+				return s.syncChecklistParent(tx, userID, todoID)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
-		// 重新查询
-		s.db.Where("id = ? AND user_id = ?", todoID, userID).First(&todo)
 	}
 
+	// 重新查询
+	s.withDetails(s.db).Where("id = ? AND user_id = ?", todoID, userID).First(&todo)
 	return &todo, nil
 }
 
-// DeleteTodo 删除待办事项
-func (s *TodoService) DeleteTodo(userID, todoID int64) error {
-	result := s.db.Where("id = ? AND user_id = ?", todoID, userID).Delete(&model.Todo{})
-	if result.Error != nil {
-		return errors.New("删除失败")
-	}
-	if result.RowsAffected == 0 {
-		return errors.New("待办事项不存在")
-	}
-	return nil
+// DeleteTodo 删除待办事项，ifMatch 不为空时只在事项的 ETag 与其匹配时删除
+func (s *TodoService) DeleteTodo(userID, todoID int64, ifMatch string) error {
+	defer s.changed(userID)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		todo, err := s.findTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		if err := checkIfMatch(todo, ifMatch); err != nil {
+			return err
+		}
+
+		// 软删除，检查项和依赖关系保留以便从回收站恢复
+		query := tx
+		if ifMatch != "" && ifMatch != "*" {
+			query = query.Where("version = ?", todo.Version)
+		}
+		result := query.Delete(todo)
+		if result.Error != nil {
+			return errors.New("删除失败")
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("待办事项已被修改")
+		}
+		if err := touchDependents(tx, userID, []int64{todo.ID}); err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, todo, model.ActivityDeleted, "", nil)
+	})
 }
 
-// ToggleTodo 切换待办事项状态
-func (s *TodoService) ToggleTodo(userID, todoID int64) (*model.Todo, error) {
+// ToggleTodo 切换待办事项状态，force 为 true 时允许完成仍有未完成前置事项的事项，
+// ifMatch 不为空时只在事项的 ETag 与其匹配时切换
+func (s *TodoService) ToggleTodo(userID, todoID int64, force bool, ifMatch string) (*model.Todo, error) {
+	defer s.changed(userID)
+
 	var todo model.Todo
-	if err := s.db.Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, errors.New("待办事项不存在")
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 与依赖变更使用同一把锁，检查前置事项后到更新前依赖关系不会改变
+		if err := lockDependencies(tx, userID); err != nil {
+			return err
+		}
+		if err := tx.Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.New("待办事项不存在")
+			}
+			return errors.New("查询失败")
+		}
+		if err := checkIfMatch(&todo, ifMatch); err != nil {
+			return err
 		}
-		return nil, errors.New("查询失败")
-	}
 
-	// 切换状态
-	updates := make(map[string]interface{})
-	if todo.Status == 0 {
-		updates["status"] = 1
-		now := time.Now()
-		updates["completed_at"] = &now
-	} else {
-		updates["status"] = 0
-		updates["completed_at"] = nil
-	}
+		// 完成前检查前置事项
+		if todo.Status == 0 && !force {
+			blocked, err := s.hasPendingBlockers(tx, todoID)
+			if err != nil {
+				return err
+			}
+			if blocked {
+				return errors.New("存在未完成的前置事项")
+			}
+		}
+
+		// 切换状态
+		updates := make(map[string]interface{})
+		if todo.Status == 0 {
+			updates["status"] = 1
+			now := time.Now()
+			updates["completed_at"] = &now
+		} else {
+			updates["status"] = 0
+			updates["completed_at"] = nil
+		}
 
-	if err := s.db.Model(&todo).Updates(updates).Error; err != nil {
-		return nil, errors.New("更新失败")
+		before := todo
+		if err := updateTodoRow(tx, &todo, updates, ifMatch); err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, &todo, updateAction(&before, &todo), "", diffTodo(&before, &todo))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// 重新查询
-	s.db.Where("id = ? AND user_id = ?", todoID, userID).First(&todo)
+	s.withDetails(s.db).Where("id = ? AND user_id = ?", todoID, userID).First(&todo)
 	return &todo, nil
 }
 
-// BatchComplete 批量完成所有待办事项
-func (s *TodoService) BatchComplete(userID int64) (int64, error) {
-	now := time.Now()
-	result := s.db.Model(&model.Todo{}).
-		Where("user_id = ? AND status = ?", userID, 0).
-		Updates(map[string]interface{}{
+// BatchComplete 批量完成所有待办事项，projectID 大于 0 时仅作用于该清单。
+// 存在未完成前置事项的事项不会完成，force 为 true 时一并完成
+func (s *TodoService) BatchComplete(userID, projectID int64, force bool) (*model.BatchOperationResult, error) {
+	defer s.changed(userID)
+
+	var result *model.BatchOperationResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := lockDependencies(tx, userID); err != nil {
+			return err
+		}
+		todos, err := findTodos(s.batchScope(tx, userID, projectID).Where("status = ?", 0))
+		if err != nil {
+			return errors.New("批量操作失败")
+		}
+		var blocked map[int64]bool
+		if !force {
+			if blocked, err = pendingBlockedIDs(tx, todoIDs(todos)); err != nil {
+				return err
+			}
+			todos = excludeTodos(todos, blocked)
+		}
+		affected, err := batchUpdate(tx, userID, todos, map[string]interface{}{
 			"status":       1,
 			"completed_at": &now,
-		})
-
-	if result.Error != nil {
-		return 0, errors.New("批量操作失败")
-	}
-	return result.RowsAffected, nil
+		}, "batch.complete")
+		if err != nil {
+			return err
+		}
+		result, err = batchResult(tx, userID, "batch.complete", false, todos, affected)
+		if err != nil {
+			return err
+		}
+		result.BlockedCount = int64(len(blocked))
+		return nil
+	})
+	return result, err
 }
 
-// BatchPending 批量重置所有已完成事项
-func (s *TodoService) BatchPending(userID int64) (int64, error) {
-	result := s.db.Model(&model.Todo{}).
-		Where("user_id = ? AND status = ?", userID, 1).
-		Updates(map[string]interface{}{
+// BatchPending 批量重置所有已完成事项，projectID 大于 0 时仅作用于该清单
+func (s *TodoService) BatchPending(userID, projectID int64) (*model.BatchOperationResult, error) {
+	defer s.changed(userID)
+
+	var result *model.BatchOperationResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		todos, err := findTodos(s.batchScope(tx, userID, projectID).Where("status = ?", 1))
+		if err != nil {
+			return errors.New("批量操作失败")
+		}
+		affected, err := batchUpdate(tx, userID, todos, map[string]interface{}{
 			"status":       0,
 			"completed_at": nil,
-		})
+		}, "batch.pending")
+		if err != nil {
+			return err
+		}
+		result, err = batchResult(tx, userID, "batch.pending", false, todos, affected)
+		return err
+	})
+	return result, err
+}
+
+// BatchClearCompleted 批量删除已完成事项，projectID 大于 0 时仅作用于该清单
+func (s *TodoService) BatchClearCompleted(userID, projectID int64) (*model.BatchOperationResult, error) {
+	return s.batchDelete(userID, projectID, 1, "batch.clear_completed")
+}
+
+// BatchClearPending 批量删除待办事项，projectID 大于 0 时仅作用于该清单
+func (s *TodoService) BatchClearPending(userID, projectID int64) (*model.BatchOperationResult, error) {
+	return s.batchDelete(userID, projectID, 0, "batch.clear_pending")
+}
+
+// batchDelete 批量删除（移入回收站）指定状态的事项
+func (s *TodoService) batchDelete(userID, projectID int64, status int, source string) (*model.BatchOperationResult, error) {
+	defer s.changed(userID)
+
+	var result *model.BatchOperationResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		todos, err := findTodos(s.batchScope(tx, userID, projectID).Where("status = ?", status))
+		if err != nil {
+			return errors.New("批量删除失败")
+		}
+		affected, err := batchSoftDelete(tx, userID, todos, source)
+		if err != nil {
+			return err
+		}
+		result, err = batchResult(tx, userID, source, true, todos, affected)
+		return err
+	})
+	return result, err
+}
 
+// findTodos 查询范围内的全部事项
+func findTodos(query *gorm.DB) ([]model.Todo, error) {
+	var todos []model.Todo
+	if err := query.Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// batchUpdate 对给定事项执行相同的更新，并逐条记录动态
+func batchUpdate(tx *gorm.DB, userID int64, todos []model.Todo, updates map[string]interface{}, source string) (int64, error) {
+	if len(todos) == 0 {
+		return 0, nil
+	}
+
+	ids := todoIDs(todos)
+	result := tx.Model(&model.Todo{}).Where("id IN ?", ids).Updates(bumpVersion(updates))
 	if result.Error != nil {
 		return 0, errors.New("批量操作失败")
 	}
+
+	var after []model.Todo
+	if err := tx.Where("id IN ?", ids).Find(&after).Error; err != nil {
+		return 0, errors.New("批量操作失败")
+	}
+	afterByID := make(map[int64]*model.Todo, len(after))
+	for i := range after {
+		afterByID[after[i].ID] = &after[i]
+	}
+
+	for i := range todos {
+		updated, ok := afterByID[todos[i].ID]
+		if !ok {
+			continue
+		}
+		changes := diffTodo(&todos[i], updated)
+		if len(changes) == 0 {
+			continue
+		}
+		if err := recordRevision(tx, &todos[i], updated); err != nil {
+			return 0, err
+		}
+		if err := recordActivity(tx, userID, updated, updateAction(&todos[i], updated), source, changes); err != nil {
+			return 0, err
+		}
+	}
 	return result.RowsAffected, nil
 }
 
-// BatchClearCompleted 批量删除已完成事项
-func (s *TodoService) BatchClearCompleted(userID int64) (int64, error) {
-	result := s.db.Where("user_id = ? AND status = ?", userID, 1).Delete(&model.Todo{})
+// batchSoftDelete 将给定事项移入回收站，并逐条记录动态
+func batchSoftDelete(tx *gorm.DB, userID int64, todos []model.Todo, source string) (int64, error) {
+	if len(todos) == 0 {
+		return 0, nil
+	}
+
+	ids := todoIDs(todos)
+	result := tx.Where("id IN ?", ids).Delete(&model.Todo{})
 	if result.Error != nil {
 		return 0, errors.New("批量删除失败")
 	}
+	if err := touchDependents(tx, userID, ids); err != nil {
+		return 0, err
+	}
+
+	for i := range todos {
+		if err := recordActivity(tx, userID, &todos[i], model.ActivityDeleted, source, nil); err != nil {
+			return 0, err
+		}
+	}
 	return result.RowsAffected, nil
 }
 
-// BatchClearPending 批量删除待办事项
-func (s *TodoService) BatchClearPending(userID int64) (int64, error) {
-	result := s.db.Where("user_id = ? AND status = ?", userID, 0).Delete(&model.Todo{})
-	if result.Error != nil {
-		return 0, errors.New("批量删除失败")
+// todoIDs 提取事项ID
+func todoIDs(todos []model.Todo) []int64 {
+	ids := make([]int64, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
 	}
-	return result.RowsAffected, nil
+	return ids
 }
 
-// GetStats 获取统计信息
-func (s *TodoService) GetStats(userID int64) (*model.TodoStats, error) {
-	var total int64
-	var completed int64
-	var pending int64
+// applyFilter 应用过滤条件，列表接口和选择性批量操作共用
+func (s *TodoService) applyFilter(query *gorm.DB, filter *model.TodoFilter) (*gorm.DB, error) {
+	// 状态过滤
+	if filter.Status == "pending" {
+		query = query.Where("status = ?", 0)
+	} else if filter.Status == "completed" {
+		query = query.Where("status = ?", 1)
+	}
 
-	// 统计总数
-	s.db.Model(&model.Todo{}).Where("user_id = ?", userID).Count(&total)
+	// 清单过滤
+	if filter.ProjectID > 0 {
+		query = query.Where("project_id = ?", filter.ProjectID)
+	}
 
-	// 统计已完成
-	s.db.Model(&model.Todo{}).Where("user_id = ? AND status = ?", userID, 1).Count(&completed)
+	// 优先级过滤
+	if filter.Priority != "" {
+		priorities, err := parsePriorityFilter(filter.Priority)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("priority IN ?", priorities)
+	}
+
+	// 阻塞状态过滤
+	if filter.Blocked == "true" {
+		query = query.Where(pendingBlockerSQL)
+	} else if filter.Blocked == "false" {
+		query = query.Where("NOT " + pendingBlockerSQL)
+	}
+
+	// 关键词搜索
+	if filter.Keyword != "" {
+		query = query.Where("title LIKE ? OR content LIKE ?", "%"+filter.Keyword+"%", "%"+filter.Keyword+"%")
+	}
+
+	return query, nil
+}
+
+// sortableColumns 允许排序的字段
+var sortableColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"deadline":   true,
+	"title":      true,
+	"status":     true,
+	"priority":   true,
+}
+
+// applySort 应用排序，不支持的字段回退为按创建时间排序
+func (s *TodoService) applySort(query *gorm.DB, sortBy, sortOrder string) *gorm.DB {
+	desc := strings.ToLower(sortOrder) != "asc"
+
+	if sortBy == "urgency" {
+		return query.
+			Order(urgencyOrder(time.Now(), desc)).
+			Order("deadline IS NULL, deadline asc").
+			Order("id desc")
+	}
+
+	if !sortableColumns[sortBy] {
+		sortBy = "created_at"
+	}
+	return query.Order(clause.OrderByColumn{Column: clause.Column{Name: sortBy}, Desc: desc})
+}
+
+// urgencyOrder 构建紧急度排序表达式。
+// 已完成事项紧急度为 0；未完成事项为 优先级×10 加上截止时间临近程度：
+// 已逾期 +50，24小时内 +30，3天内 +20，7天内 +10
+func urgencyOrder(now time.Time, desc bool) clause.Expr {
+	direction := "DESC"
+	if !desc {
+		direction = "ASC"
+	}
+	return clause.Expr{
+		SQL: "CASE WHEN status = 1 THEN 0 ELSE priority * 10 + CASE" +
+			" WHEN deadline IS NULL THEN 0" +
+			" WHEN deadline < ? THEN 50" +
+			" WHEN deadline < ? THEN 30" +
+			" WHEN deadline < ? THEN 20" +
+			" WHEN deadline < ? THEN 10" +
+			" ELSE 0 END END " + direction,
+		Vars: []interface{}{
+			now,
+			now.Add(24 * time.Hour),
+			now.Add(3 * 24 * time.Hour),
+			now.Add(7 * 24 * time.Hour),
+		},
+		WithoutParentheses: true,
+	}
+}
+
+// parsePriorityFilter 解析优先级过滤参数，支持名称和数字，逗号分隔
+func parsePriorityFilter(value string) ([]int, error) {
+	var priorities []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		if p, ok := model.PriorityNames[part]; ok {
+			priorities = append(priorities, p)
+			continue
+		}
+		p, err := strconv.Atoi(part)
+		if err != nil || !validPriority(p) {
+			return nil, errors.New("优先级参数无效")
+		}
+		priorities = append(priorities, p)
+	}
+	if len(priorities) == 0 {
+		return nil, errors.New("优先级参数无效")
+	}
+	return priorities, nil
+}
+
+// validPriority 校验优先级取值
+func validPriority(priority int) bool {
+	return priority >= model.PriorityNone && priority <= model.PriorityUrgent
+}
 
-	// 统计待办
-	s.db.Model(&model.Todo{}).Where("user_id = ? AND status = ?", userID, 0).Count(&pending)
+// withDetails 预加载待办事项的关联数据
+func (s *TodoService) withDetails(query *gorm.DB) *gorm.DB {
+	return query.Preload("ChecklistItems", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order asc, id asc")
+	}).
+		Preload("BlockedBy", "blocked_by_id IN (SELECT id FROM todos WHERE deleted_at IS NULL)").
+		Preload("Blocking", "todo_id IN (SELECT id FROM todos WHERE deleted_at IS NULL)")
+}
+
+// batchScope 构建批量操作的查询范围
+func (s *TodoService) batchScope(tx *gorm.DB, userID, projectID int64) *gorm.DB {
+	query := tx.Model(&model.Todo{}).Where("user_id = ?", userID)
+	if projectID > 0 {
+		query = query.Where("project_id = ?", projectID)
+	}
+	return query
+}
+
+// checkProject 校验清单是否属于当前用户
+func (s *TodoService) checkProject(userID, projectID int64) error {
+	var count int64
+	if err := s.db.Model(&model.Project{}).
+		Where("id = ? AND user_id = ?", projectID, userID).
+		Count(&count).Error; err != nil {
+		return errors.New("查询失败")
+	}
+	if count == 0 {
+		return errors.New("清单不存在")
+	}
+	return nil
+}
+
+// GetStats 获取统计信息
+func (s *TodoService) GetStats(userID int64) (*model.TodoStats, error) {
+	return cache.Fetch(s.cache, userID, "stats", "", StatsCacheTTL, func() (*model.TodoStats, error) {
+		return s.queryStats(userID)
+	})
+}
+
+// queryStats 查询统计信息。所有计数在同一条聚合查询中完成，数字之间保持一致；
+// 今天的范围按用户时区计算
+func (s *TodoService) queryStats(userID int64) (*model.TodoStats, error) {
+	loc, err := s.statsLocation(userID, "")
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	today := localDate(now, loc)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	var counts struct {
+		Total     int64
+		Completed int64
+		Pending   int64
+		Overdue   int64
+		DueToday  int64
+	}
+	if err := s.db.Model(&model.Todo{}).
+		Select("COUNT(*) AS total, "+
+			"COALESCE(SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END), 0) AS completed, "+
+			"COALESCE(SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END), 0) AS pending, "+
+			"COALESCE(SUM(CASE WHEN status = 0 AND deadline < ? THEN 1 ELSE 0 END), 0) AS overdue, "+
+			"COALESCE(SUM(CASE WHEN status = 0 AND deadline >= ? AND deadline < ? THEN 1 ELSE 0 END), 0) AS due_today",
+			now, today, tomorrow).
+		Where("user_id = ?", userID).
+		Scan(&counts).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
 
 	// 计算完成率
 	var completionRate float64
-	if total > 0 {
-		completionRate = float64(completed) / float64(total)
+	if counts.Total > 0 {
+		completionRate = float64(counts.Completed) / float64(counts.Total)
 	}
 
 	return &model.TodoStats{
-		Total:          total,
-		Pending:        pending,
-		Completed:      completed,
+		Total:          counts.Total,
+		Pending:        counts.Pending,
+		Completed:      counts.Completed,
 		CompletionRate: completionRate,
+		Overdue:        counts.Overdue,
+		DueToday:       counts.DueToday,
 	}, nil
 }
 
@@ -306,8 +777,21 @@ func (s *TodoService) todoToResponse(todo *model.Todo) model.TodoResponse {
 		Title:     todo.Title,
 		Content:   todo.Content,
 		Status:    todo.Status,
+		Priority:  todo.Priority,
+		ProjectID: todo.ProjectID,
 		CreatedAt: todo.CreatedAt.Unix(),
 		UpdatedAt: todo.UpdatedAt.Unix(),
+		Version:   todo.Version,
+		Progress:  ChecklistProgress(todo.ChecklistItems),
+		BlockedBy: BlockedByIDs(todo),
+		Blocking:  BlockingIDs(todo),
+
+		ChecklistAutoComplete: todo.ChecklistAutoComplete,
+	}
+
+	if todo.DeletedAt.Valid {
+		deletedAt := todo.DeletedAt.Time.Unix()
+		resp.DeletedAt = &deletedAt
 	}
 
 	if todo.Deadline != nil {
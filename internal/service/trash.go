@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// TrashRetentionDays 回收站保留天数，超过后会被永久删除，服务启动时可由 REMINDGO_TRASH_RETENTION_DAYS 覆盖
+	TrashRetentionDays = 30
+	// TrashPurgeInterval 回收站清理任务执行间隔
+	TrashPurgeInterval = time.Hour
+)
+
+// GetTrash 获取回收站中的待办事项，按删除时间倒序
+func (s *TodoService) GetTrash(userID int64, params *model.TrashQueryParams) (*model.TodoListResponse, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 10
+	}
+	if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+
+	query := s.db.Unscoped().Model(&model.Todo{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+	var todos []model.Todo
+	if err := query.Preload("ChecklistItems").Order("deleted_at desc").
+		Offset(offset).Limit(params.PageSize).Find(&todos).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	items := make([]model.TodoResponse, len(todos))
+	for i, todo := range todos {
+		items[i] = s.todoToResponse(&todo)
+	}
+
+	return &model.TodoListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(params.PageSize))),
+	}, nil
+}
+
+// RestoreTodo 从回收站恢复待办事项
+func (s *TodoService) RestoreTodo(userID, todoID int64) (*model.Todo, error) {
+	defer s.changed(userID)
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var todo model.Todo
+		if err := tx.Unscoped().
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", todoID, userID).
+			First(&todo).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.New("回收站中不存在该事项")
+			}
+			return errors.New("恢复失败")
+		}
+
+		if err := tx.Unscoped().Model(&todo).Updates(bumpVersion(map[string]interface{}{"deleted_at": nil})).Error; err != nil {
+			return errors.New("恢复失败")
+		}
+		if err := touchDependents(tx, userID, []int64{todo.ID}); err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, &todo, model.ActivityRestored, "", nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadTodo(userID, todoID)
+}
+
+// PurgeTodo 永久删除回收站中的待办事项
+func (s *TodoService) PurgeTodo(userID, todoID int64) error {
+	defer s.changed(userID)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		todos, err := findTodos(tx.Unscoped().
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", todoID, userID))
+		if err != nil {
+			return errors.New("删除失败")
+		}
+		if len(todos) == 0 {
+			return errors.New("回收站中不存在该事项")
+		}
+		if err := purgeTodos(tx, userID, todos); err != nil {
+			return errors.New("删除失败")
+		}
+		return nil
+	})
+}
+
+// EmptyTrash 清空回收站
+func (s *TodoService) EmptyTrash(userID int64) (int64, error) {
+	defer s.changed(userID)
+
+	var affected int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		todos, err := findTodos(tx.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID))
+		if err != nil {
+			return errors.New("清空回收站失败")
+		}
+		if len(todos) == 0 {
+			return nil
+		}
+		if err := purgeTodos(tx, userID, todos); err != nil {
+			return errors.New("清空回收站失败")
+		}
+		affected = int64(len(todos))
+		return nil
+	})
+	return affected, err
+}
+
+// PurgeExpiredTrash 永久删除所有用户在回收站中超过保留期限的事项。
+// 回收站中的事项不会被缓存，因此不需要使缓存失效，只通知实时连接
+func (s *TodoService) PurgeExpiredTrash(retentionDays int) (int64, error) {
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	var affected int64
+	users := make(map[int64]bool)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		todos, err := findTodos(tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff))
+		if err != nil {
+			return err
+		}
+		if len(todos) == 0 {
+			return nil
+		}
+		// 系统任务清理，操作者记为 0
+		if err := purgeTodos(tx, 0, todos); err != nil {
+			return err
+		}
+		affected = int64(len(todos))
+		for _, todo := range todos {
+			users[todo.UserID] = true
+		}
+		return nil
+	})
+	if err == nil {
+		for userID := range users {
+			s.broker.Publish(userID)
+		}
+	}
+	return affected, err
+}
+
+// RunTrashPurger 定期清理回收站中过期的事项和过期的撤销记录，直到 ctx 结束
+func (s *TodoService) RunTrashPurger(ctx context.Context) {
+	ticker := time.NewTicker(TrashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := s.PurgeExpiredTrash(TrashRetentionDays)
+		if err != nil {
+			log.Printf("Failed to purge trash: %v", err)
+		} else if count > 0 {
+			log.Printf("Purged %d expired todos from trash", count)
+		}
+
+		// 顺便清理过期的批量操作撤销记录
+		if _, err := s.PurgeExpiredUndo(); err != nil {
+			log.Printf("Failed to purge expired undo records: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTodos 永久删除待办事项及其检查项、依赖关系和版本，并记录动态
+func purgeTodos(tx *gorm.DB, actorID int64, todos []model.Todo) error {
+	ids := todoIDs(todos)
+	if err := tx.Where("todo_id IN ?", ids).Delete(&model.ChecklistItem{}).Error; err != nil {
+		return err
+	}
+	// 依赖关系的另一端事项会失去对应的前置或后续事项
+	if err := touchDependents(tx, actorID, ids); err != nil {
+		return err
+	}
+	if err := tx.Where("todo_id IN ? OR blocked_by_id IN ?", ids, ids).
+		Delete(&model.TodoDependency{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("todo_id IN ?", ids).Delete(&model.TodoRevision{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("todo_id IN ?", ids).Delete(&model.CalendarObject{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("id IN ?", ids).Delete(&model.Todo{}).Error; err != nil {
+		return err
+	}
+
+	for i := range todos {
+		if err := recordActivity(tx, actorID, &todos[i], model.ActivityPurged, "", nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
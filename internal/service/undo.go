@@ -0,0 +1,214 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BatchUndoWindow 批量操作可撤销的时间窗口
+var BatchUndoWindow = 10 * time.Minute
+
+// UndoBatch 撤销批量操作，将受影响的事项恢复到操作前的状态。
+// 已被永久删除的事项无法恢复，会被忽略
+func (s *TodoService) UndoBatch(userID int64, token string) (*model.BatchOperationResult, error) {
+	defer s.changed(userID)
+
+	if token == "" {
+		return nil, errors.New("撤销令牌无效")
+	}
+
+	var restored int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定撤销记录，防止同一令牌被并发使用
+		var undo model.BatchUndo
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token = ? AND user_id = ?", token, userID).
+			First(&undo).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.New("撤销令牌无效")
+			}
+			return errors.New("撤销失败")
+		}
+		if undo.UndoneAt != nil {
+			return errors.New("该操作已撤销")
+		}
+		now := time.Now()
+		if now.After(undo.ExpiresAt) {
+			return errors.New("撤销令牌已过期")
+		}
+
+		var snapshots []model.TodoSnapshot
+		if err := json.Unmarshal([]byte(undo.Snapshot), &snapshots); err != nil {
+			return errors.New("撤销失败")
+		}
+
+		var err error
+		if restored, err = restoreSnapshots(tx, userID, undo.Source, snapshots); err != nil {
+			return err
+		}
+		ids := make([]int64, len(snapshots))
+		for i, snapshot := range snapshots {
+			ids[i] = snapshot.ID
+		}
+		if err := enqueueWebhookEvent(tx, userID, "batch.undo", model.WebhookBatchEvent{
+			AffectedCount: restored,
+			TodoIDs:       ids,
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&undo).Update("undone_at", &now).Error; err != nil {
+			return errors.New("撤销失败")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BatchOperationResult{AffectedCount: restored}, nil
+}
+
+// PurgeExpiredUndo 删除已过期的撤销记录
+func (s *TodoService) PurgeExpiredUndo() (int64, error) {
+	result := s.db.Where("expires_at < ?", time.Now()).Delete(&model.BatchUndo{})
+	return result.RowsAffected, result.Error
+}
+
+// batchResult 生成批量操作结果，有事项受影响时保存撤销快照并返回撤销令牌，同时生成批量事件
+func batchResult(tx *gorm.DB, userID int64, source string, deleted bool, todos []model.Todo, affected int64) (*model.BatchOperationResult, error) {
+	result := &model.BatchOperationResult{AffectedCount: affected}
+	if affected == 0 || len(todos) == 0 {
+		return result, nil
+	}
+
+	snapshots := make([]model.TodoSnapshot, len(todos))
+	for i, todo := range todos {
+		snapshots[i] = model.TodoSnapshot{
+			ID:                    todo.ID,
+			Deleted:               deleted,
+			Status:                todo.Status,
+			CompletedAt:           todo.CompletedAt,
+			Deadline:              todo.Deadline,
+			ProjectID:             todo.ProjectID,
+			Priority:              todo.Priority,
+			ChecklistAutoComplete: todo.ChecklistAutoComplete,
+		}
+	}
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return nil, errors.New("批量操作失败")
+	}
+
+	token, err := randomToken(16)
+	if err != nil {
+		return nil, errors.New("批量操作失败")
+	}
+	undo := model.BatchUndo{
+		UserID:    userID,
+		Token:     token,
+		Source:    source,
+		Snapshot:  string(data),
+		ExpiresAt: time.Now().Add(BatchUndoWindow),
+	}
+	if err := tx.Create(&undo).Error; err != nil {
+		return nil, errors.New("批量操作失败")
+	}
+
+	expiresAt := undo.ExpiresAt.Unix()
+	result.UndoToken = undo.Token
+	result.UndoExpiresAt = &expiresAt
+
+	err = enqueueWebhookEvent(tx, userID, source, model.WebhookBatchEvent{
+		AffectedCount: affected,
+		TodoIDs:       todoIDs(todos),
+		UndoToken:     undo.Token,
+	})
+	return result, err
+}
+
+// restoreSnapshots 将事项恢复到快照中的状态，并逐条记录动态
+func restoreSnapshots(tx *gorm.DB, userID int64, source string, snapshots []model.TodoSnapshot) (int64, error) {
+	ids := make([]int64, len(snapshots))
+	var projectIDs []int64
+	for i, snapshot := range snapshots {
+		ids[i] = snapshot.ID
+		if snapshot.ProjectID != nil {
+			projectIDs = append(projectIDs, *snapshot.ProjectID)
+		}
+	}
+
+	var todos []model.Todo
+	if err := tx.Unscoped().Where("user_id = ? AND id IN ?", userID, ids).Find(&todos).Error; err != nil {
+		return 0, errors.New("撤销失败")
+	}
+	todoByID := make(map[int64]*model.Todo, len(todos))
+	for i := range todos {
+		todoByID[todos[i].ID] = &todos[i]
+	}
+
+	// 操作之后被删除的清单不再恢复关联
+	existingProjects := make(map[int64]bool)
+	if len(projectIDs) > 0 {
+		var existing []int64
+		if err := tx.Model(&model.Project{}).
+			Where("user_id = ? AND id IN ?", userID, projectIDs).
+			Pluck("id", &existing).Error; err != nil {
+			return 0, errors.New("撤销失败")
+		}
+		for _, id := range existing {
+			existingProjects[id] = true
+		}
+	}
+
+	source = source + ".undo"
+	var restored int64
+	for _, snapshot := range snapshots {
+		todo, ok := todoByID[snapshot.ID]
+		if !ok {
+			continue
+		}
+
+		updates := map[string]interface{}{
+			"status":                  snapshot.Status,
+			"completed_at":            snapshot.CompletedAt,
+			"deadline":                snapshot.Deadline,
+			"priority":                snapshot.Priority,
+			"checklist_auto_complete": snapshot.ChecklistAutoComplete,
+			"project_id":              nil,
+		}
+		if snapshot.ProjectID != nil && existingProjects[*snapshot.ProjectID] {
+			updates["project_id"] = *snapshot.ProjectID
+		}
+		wasDeleted := todo.DeletedAt.Valid
+		if snapshot.Deleted {
+			updates["deleted_at"] = nil
+		}
+
+		before := *todo
+		if err := tx.Unscoped().Model(todo).Updates(bumpVersion(updates)).Error; err != nil {
+			return 0, errors.New("撤销失败")
+		}
+		if snapshot.Deleted && wasDeleted {
+			if err := touchDependents(tx, userID, []int64{todo.ID}); err != nil {
+				return 0, err
+			}
+			if err := recordActivity(tx, userID, todo, model.ActivityRestored, source, nil); err != nil {
+				return 0, err
+			}
+		}
+		if changes := diffTodo(&before, todo); len(changes) > 0 {
+			if err := recordActivity(tx, userID, todo, updateAction(&before, todo), source, changes); err != nil {
+				return 0, err
+			}
+		}
+		restored++
+	}
+	return restored, nil
+}
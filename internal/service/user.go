@@ -2,7 +2,9 @@ package service
 
 import (
 	"errors"
+	"time"
 
+	"RemindGo/internal/cache"
 	"RemindGo/internal/model"
 
 	"golang.org/x/crypto/bcrypt"
@@ -11,12 +13,13 @@ import (
 
 // UserService 用户服务
 type UserService struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cache *cache.Store
 }
 
-// NewUserService 创建用户服务
-func NewUserService(db *gorm.DB) *UserService {
-	return &UserService{db: db}
+// NewUserService 创建用户服务，修改时区后使用户的缓存失效（统计按时区划分日期）
+func NewUserService(db *gorm.DB, store *cache.Store) *UserService {
+	return &UserService{db: db, cache: store}
 }
 
 // Register 用户注册
@@ -119,11 +122,22 @@ func (s *UserService) UpdateUser(userID int64, req *model.UpdateUserRequest) (*m
 		updates["email"] = *req.Email
 	}
 
+	if req.TimeZone != nil {
+		if _, err := LoadTimeZone(*req.TimeZone); err != nil {
+			return nil, err
+		}
+		updates["time_zone"] = *req.TimeZone
+	}
+
 	// 执行更新
 	if len(updates) > 0 {
 		if err := s.db.Model(&user).Updates(updates).Error; err != nil {
 			return nil, errors.New("更新失败")
 		}
+		// 统计按时区划分日期，时区变化后缓存的统计不再正确
+		if req.TimeZone != nil {
+			s.cache.Invalidate(userID)
+		}
 		// 重新查询用户信息
 		s.db.First(&user, userID)
 	}
@@ -147,3 +161,15 @@ func (s *UserService) DeleteUser(userID int64) error {
 func (s *UserService) VerifyPassword(user *model.User, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 }
+
+// LoadTimeZone 解析 IANA 时区名称，为空时为 UTC
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("时区无效")
+	}
+	return loc, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// TodoETag 事项的实体标签，由事项ID和版本号组成，事项每次修改都会变化
+func TodoETag(todo *model.Todo) string {
+	return fmt.Sprintf(`"%d-%d"`, todo.ID, todo.Version)
+}
+
+// MatchETag 使用弱比较检查 If-None-Match 请求头是否与实体标签匹配，
+// 支持 * 和逗号分隔的多个标签，忽略弱标签的 W/ 前缀
+func MatchETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchETagStrong 使用强比较检查 If-Match 请求头是否与实体标签匹配（RFC 7232），弱标签不匹配任何实体
+func MatchETagStrong(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch 检查 If-Match 前置条件，ifMatch 为空表示不检查
+func checkIfMatch(todo *model.Todo, ifMatch string) error {
+	if ifMatch != "" && !MatchETagStrong(ifMatch, TodoETag(todo)) {
+		return errors.New("待办事项已被修改")
+	}
+	return nil
+}
+
+// bumpVersion 返回附加了版本号递增的更新字段，不修改传入的 map
+func bumpVersion(updates map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(updates)+1)
+	for key, value := range updates {
+		values[key] = value
+	}
+	values["version"] = gorm.Expr("version + 1")
+	return values
+}
+
+// updateTodoRow 更新单个事项并递增版本号。ifMatch 为具体的 ETag 时只在版本未被并发修改时更新，
+// 否则返回 "待办事项已被修改"
+func updateTodoRow(tx *gorm.DB, todo *model.Todo, updates map[string]interface{}, ifMatch string) error {
+	query := tx.Model(todo)
+	if ifMatch != "" && ifMatch != "*" {
+		query = query.Where("version = ?", todo.Version)
+	}
+	result := query.Updates(bumpVersion(updates))
+	if result.Error != nil {
+		return errors.New("更新失败")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("待办事项已被修改")
+	}
+	todo.Version++
+	return nil
+}
+
+// touchTodos 递增事项的版本号并记录更新动态，用于检查项、依赖关系等关联数据变化的情况，
+// 使事项的 ETag 失效，增量同步和实时推送也能得知变化。回收站中的事项只递增版本号
+func touchTodos(tx *gorm.DB, actorID int64, source string, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Unscoped().Model(&model.Todo{}).Where("id IN ?", ids).
+		Updates(bumpVersion(nil)).Error; err != nil {
+		return errors.New("更新失败")
+	}
+
+	todos, err := findTodos(tx.Where("id IN ?", ids))
+	if err != nil {
+		return errors.New("查询失败")
+	}
+	for i := range todos {
+		if err := recordActivity(tx, actorID, &todos[i], model.ActivityUpdated, source, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// touchDependents 对与给定事项存在依赖关系的其他事项调用 touchTodos。
+// 事项删除、恢复或永久删除时，另一端事项响应中的 blocked_by 和 blocking 会随之变化
+func touchDependents(tx *gorm.DB, actorID int64, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var blocked, blockers []int64
+	if err := tx.Model(&model.TodoDependency{}).Where("blocked_by_id IN ? AND todo_id NOT IN ?", ids, ids).
+		Pluck("todo_id", &blocked).Error; err != nil {
+		return errors.New("查询失败")
+	}
+	if err := tx.Model(&model.TodoDependency{}).Where("todo_id IN ? AND blocked_by_id NOT IN ?", ids, ids).
+		Pluck("blocked_by_id", &blockers).Error; err != nil {
+		return errors.New("查询失败")
+	}
+	return touchTodos(tx, actorID, "dependency", append(blocked, blockers...)...)
+}
@@ -0,0 +1,779 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"RemindGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// Webhook 投递参数
+var (
+	MaxWebhooksPerUser       = 20
+	WebhookPollInterval      = 5 * time.Second     // 检查待发送投递的间隔
+	WebhookTimeout           = 10 * time.Second    // 单次请求超时
+	WebhookMaxAttempts       = 8                   // 最多尝试次数，之后标记为失败
+	WebhookRetryBase         = 30 * time.Second    // 第一次重试的等待时间，之后每次翻倍
+	WebhookRetryMax          = 12 * time.Hour      // 重试等待时间的上限
+	WebhookDeliveryRetention = 30 * 24 * time.Hour // 投递记录的保留时间
+)
+
+const (
+	webhookPurgeInterval = time.Hour // 清理过期投递记录的间隔
+	webhookResponseLimit = 1024      // 保存的响应内容长度
+	webhookDispatchBatch = 50        // 每次检查最多发送的投递数
+	webhookWorkers       = 4         // 并发发送的请求数
+	webhookUserAgent     = "RemindGo-Webhook/1.0"
+)
+
+// WebhookService Webhook 服务
+type WebhookService struct {
+	db     *gorm.DB
+	client *http.Client
+}
+
+// NewWebhookService 创建 Webhook 服务
+func NewWebhookService(db *gorm.DB) *WebhookService {
+	return &WebhookService{
+		db: db,
+		client: &http.Client{
+			Timeout:   WebhookTimeout,
+			Transport: webhookTransport(),
+			// 不跟随重定向，3xx 按失败处理
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// webhookTransport 发送 Webhook 使用的连接，在 DNS 解析之后检查实际连接的地址，
+// 拒绝回环、内网和链路本地地址，防止通过 Webhook 访问内部服务（包括 DNS 重绑定）。
+// 不使用环境变量中的代理，否则检查的是代理的地址
+func webhookTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: WebhookTimeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || blockedWebhookIP(ip) {
+				return errors.New("Webhook 地址不能指向内网: " + host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// cgnatNetwork 运营商级 NAT 使用的共享地址段
+var cgnatNetwork = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// blockedWebhookIP Webhook 不允许访问的地址：回环、内网、链路本地（包括云服务器元数据地址）、未指定和组播地址
+func blockedWebhookIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		if ip4[0] == 0 || cgnatNetwork.Contains(ip4) {
+			return true
+		}
+	}
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// GetWebhooks 获取 Webhook 列表
+func (s *WebhookService) GetWebhooks(userID int64) ([]model.WebhookResponse, error) {
+	var hooks []model.Webhook
+	if err := s.db.Where("user_id = ?", userID).Order("id asc").Find(&hooks).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+	items := make([]model.WebhookResponse, len(hooks))
+	for i := range hooks {
+		items[i] = webhookToResponse(&hooks[i], false)
+	}
+	return items, nil
+}
+
+// GetWebhook 获取单个 Webhook
+func (s *WebhookService) GetWebhook(userID, webhookID int64) (*model.WebhookResponse, error) {
+	hook, err := s.findWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	resp := webhookToResponse(hook, false)
+	return &resp, nil
+}
+
+// findWebhook 查询属于当前用户的 Webhook
+func (s *WebhookService) findWebhook(userID, webhookID int64) (*model.Webhook, error) {
+	var hook model.Webhook
+	if err := s.db.Where("id = ? AND user_id = ?", webhookID, userID).First(&hook).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("Webhook 不存在")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return &hook, nil
+}
+
+// CreateWebhook 创建 Webhook，签名密钥只在创建时返回
+func (s *WebhookService) CreateWebhook(userID int64, req *model.CreateWebhookRequest) (*model.WebhookResponse, error) {
+	target, err := validateWebhookURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+	description := strings.TrimSpace(req.Description)
+	if utf8.RuneCountInString(description) > 255 {
+		return nil, errors.New("描述不能超过255个字符")
+	}
+
+	var count int64
+	if err := s.db.Model(&model.Webhook{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, errors.New("创建失败")
+	}
+	if count >= int64(MaxWebhooksPerUser) {
+		return nil, errors.New("Webhook 数量已达上限")
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, errors.New("创建失败")
+	}
+	hook := model.Webhook{
+		UserID:      userID,
+		URL:         target,
+		Events:      events,
+		Description: description,
+		Secret:      secret,
+		Active:      req.Active == nil || *req.Active,
+	}
+	if err := s.db.Create(&hook).Error; err != nil {
+		return nil, errors.New("创建失败")
+	}
+	resp := webhookToResponse(&hook, true)
+	return &resp, nil
+}
+
+// UpdateWebhook 更新 Webhook
+func (s *WebhookService) UpdateWebhook(userID, webhookID int64, req *model.UpdateWebhookRequest) (*model.WebhookResponse, error) {
+	hook, err := s.findWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.URL != nil {
+		target, err := validateWebhookURL(*req.URL)
+		if err != nil {
+			return nil, err
+		}
+		updates["url"] = target
+	}
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		updates["events"] = events
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		if utf8.RuneCountInString(description) > 255 {
+			return nil, errors.New("描述不能超过255个字符")
+		}
+		updates["description"] = description
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(hook).Updates(updates).Error; err != nil {
+			return nil, errors.New("更新失败")
+		}
+	}
+	resp := webhookToResponse(hook, false)
+	return &resp, nil
+}
+
+// DeleteWebhook 删除 Webhook 及其投递记录，未发送的投递不再发送
+func (s *WebhookService) DeleteWebhook(userID, webhookID int64) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", webhookID, userID).Delete(&model.Webhook{})
+		if result.Error != nil {
+			return errors.New("删除失败")
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("Webhook 不存在")
+		}
+		if err := tx.Where("webhook_id = ?", webhookID).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return errors.New("删除失败")
+		}
+		return nil
+	})
+}
+
+// RotateSecret 重置签名密钥，旧密钥立即失效
+func (s *WebhookService) RotateSecret(userID, webhookID int64) (*model.WebhookResponse, error) {
+	hook, err := s.findWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, errors.New("重置失败")
+	}
+	if err := s.db.Model(hook).Update("secret", secret).Error; err != nil {
+		return nil, errors.New("重置失败")
+	}
+	resp := webhookToResponse(hook, true)
+	return &resp, nil
+}
+
+// Ping 向指定的 Webhook 发送测试事件
+func (s *WebhookService) Ping(userID, webhookID int64) (*model.WebhookDeliveryResponse, error) {
+	hook, err := s.findWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if !hook.Active {
+		return nil, errors.New("Webhook 已停用")
+	}
+
+	payload, eventID, err := newWebhookPayload(model.WebhookEventPing, map[string]interface{}{
+		"webhook_id": hook.ID,
+		"events":     splitWebhookEvents(hook.Events),
+	})
+	if err != nil {
+		return nil, errors.New("发送失败")
+	}
+	delivery := newWebhookDelivery(hook, eventID, model.WebhookEventPing, payload)
+	if err := s.db.Create(&delivery).Error; err != nil {
+		return nil, errors.New("发送失败")
+	}
+	resp := webhookDeliveryToResponse(&delivery, false)
+	return &resp, nil
+}
+
+// GetDeliveries 分页获取 Webhook 的投递记录，按时间倒序
+func (s *WebhookService) GetDeliveries(userID, webhookID int64, params *model.WebhookDeliveryQueryParams) (*model.WebhookDeliveryListResponse, error) {
+	if _, err := s.findWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 20
+	}
+	if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+
+	query := s.db.Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+	if params.Event != "" {
+		query = query.Where("event = ?", params.Event)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+	var deliveries []model.WebhookDelivery
+	if err := query.Omit("payload").Order("id desc").Offset(offset).Limit(params.PageSize).Find(&deliveries).Error; err != nil {
+		return nil, errors.New("查询失败")
+	}
+
+	items := make([]model.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		items[i] = webhookDeliveryToResponse(&deliveries[i], false)
+	}
+
+	return &model.WebhookDeliveryListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(params.PageSize))),
+	}, nil
+}
+
+// GetDelivery 获取单条投递记录，包含请求体
+func (s *WebhookService) GetDelivery(userID, webhookID, deliveryID int64) (*model.WebhookDeliveryResponse, error) {
+	delivery, err := s.findDelivery(userID, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	resp := webhookDeliveryToResponse(delivery, true)
+	return &resp, nil
+}
+
+// Redeliver 重新投递，以相同的事件标识和请求体创建新的投递记录
+func (s *WebhookService) Redeliver(userID, webhookID, deliveryID int64) (*model.WebhookDeliveryResponse, error) {
+	hook, err := s.findWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if !hook.Active {
+		return nil, errors.New("Webhook 已停用")
+	}
+	original, err := s.findDelivery(userID, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.Status == model.WebhookDeliveryPending {
+		return nil, errors.New("投递尚未完成")
+	}
+
+	delivery := newWebhookDelivery(hook, original.EventID, original.Event, original.Payload)
+	if err := s.db.Create(&delivery).Error; err != nil {
+		return nil, errors.New("重新投递失败")
+	}
+	resp := webhookDeliveryToResponse(&delivery, false)
+	return &resp, nil
+}
+
+// findDelivery 查询属于当前用户指定 Webhook 的投递记录
+func (s *WebhookService) findDelivery(userID, webhookID, deliveryID int64) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := s.db.Where("id = ? AND webhook_id = ? AND user_id = ?", deliveryID, webhookID, userID).
+		First(&delivery).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("投递记录不存在")
+		}
+		return nil, errors.New("查询失败")
+	}
+	return &delivery, nil
+}
+
+// DeliverDue 发送到期的投递，返回成功的数量。
+// 发送前通过条件更新占用投递，多个实例同时运行时不会重复发送
+func (s *WebhookService) DeliverDue(now time.Time) (int, error) {
+	var due []model.WebhookDelivery
+	if err := s.db.Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+		Order("next_attempt_at asc").Limit(webhookDispatchBatch).Find(&due).Error; err != nil {
+		return 0, errors.New("查询失败")
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		delivered int
+	)
+	sem := make(chan struct{}, webhookWorkers)
+	for i := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(delivery *model.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if s.attempt(delivery, now) {
+				mu.Lock()
+				delivered++
+				mu.Unlock()
+			}
+		}(&due[i])
+	}
+	wg.Wait()
+	return delivered, nil
+}
+
+// attempt 发送一次投递并记录结果，失败时按指数退避安排重试
+func (s *WebhookService) attempt(delivery *model.WebhookDelivery, now time.Time) bool {
+	// 占用期间其他实例查询不到这条投递，超过占用时间仍未更新（例如进程退出）时会被重新发送
+	claim := s.db.Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", delivery.ID, model.WebhookDeliveryPending, now).
+		Update("next_attempt_at", now.Add(2*WebhookTimeout))
+	if claim.Error != nil || claim.RowsAffected == 0 {
+		return false
+	}
+
+	attempts := delivery.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts}
+
+	var hook model.Webhook
+	err := s.db.First(&hook, delivery.WebhookID).Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		updates["status"] = model.WebhookDeliveryFailed
+		updates["error"] = "Webhook 已删除"
+	case err != nil:
+		// 数据库暂时不可用，稍后重试，不计入尝试次数
+		return false
+	case !hook.Active:
+		updates["status"] = model.WebhookDeliveryFailed
+		updates["error"] = "Webhook 已停用"
+	default:
+		status, body, duration, sendErr := s.send(&hook, delivery)
+		updates["response_status"] = status
+		updates["response_body"] = body
+		updates["duration"] = duration.Milliseconds()
+		if sendErr == nil {
+			deliveredAt := time.Now()
+			updates["status"] = model.WebhookDeliverySuccess
+			updates["error"] = ""
+			updates["delivered_at"] = &deliveredAt
+			break
+		}
+		updates["error"] = truncateRunes(sendErr.Error(), 512)
+		if attempts >= WebhookMaxAttempts {
+			updates["status"] = model.WebhookDeliveryFailed
+		} else {
+			updates["next_attempt_at"] = time.Now().Add(webhookBackoff(attempts))
+		}
+	}
+
+	if err := s.db.Model(&model.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(updates).Error; err != nil {
+		log.Printf("Failed to record webhook delivery %d: %v", delivery.ID, err)
+	}
+	return updates["status"] == model.WebhookDeliverySuccess
+}
+
+// send 发送请求，返回响应状态码、响应内容的开头部分和耗时，非 2xx 响应返回错误
+func (s *WebhookService) send(hook *model.Webhook, delivery *model.WebhookDelivery) (int, string, time.Duration, error) {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("X-RemindGo-Event", delivery.Event)
+	req.Header.Set("X-RemindGo-Event-ID", delivery.EventID)
+	req.Header.Set("X-RemindGo-Delivery", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set("X-RemindGo-Timestamp", timestamp)
+	req.Header.Set("X-RemindGo-Signature", "sha256="+signWebhook(hook.Secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return 0, "", duration, err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	// 读完剩余内容以便复用连接
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	text := strings.ToValidUTF8(string(data), "")
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, text, duration, errors.New("对方返回状态码 " + strconv.Itoa(resp.StatusCode))
+	}
+	return resp.StatusCode, text, duration, nil
+}
+
+// signWebhook 计算签名：以密钥对 "时间戳.请求体" 做 HMAC-SHA256，结果为十六进制
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff 第 attempts 次失败后的等待时间：30秒、1分钟、2分钟……
+func webhookBackoff(attempts int) time.Duration {
+	wait := WebhookRetryBase
+	for i := 1; i < attempts && wait < WebhookRetryMax; i++ {
+		wait *= 2
+	}
+	if wait > WebhookRetryMax {
+		wait = WebhookRetryMax
+	}
+	return wait
+}
+
+// PurgeDeliveries 删除超过保留时间的投递记录，返回删除的数量
+func (s *WebhookService) PurgeDeliveries(now time.Time) (int64, error) {
+	result := s.db.Where("status <> ? AND created_at < ?", model.WebhookDeliveryPending, now.Add(-WebhookDeliveryRetention)).
+		Delete(&model.WebhookDelivery{})
+	if result.Error != nil {
+		return 0, errors.New("清理投递记录失败")
+	}
+	return result.RowsAffected, nil
+}
+
+// RunWebhookDispatcher 定期发送到期的投递并清理过期的投递记录，直到 ctx 结束
+func (s *WebhookService) RunWebhookDispatcher(ctx context.Context) {
+	ticker := time.NewTicker(WebhookPollInterval)
+	defer ticker.Stop()
+
+	var lastPurge time.Time
+	for {
+		now := time.Now()
+		if _, err := s.DeliverDue(now); err != nil {
+			log.Printf("Failed to deliver webhooks: %v", err)
+		}
+		if now.Sub(lastPurge) >= webhookPurgeInterval {
+			lastPurge = now
+			if count, err := s.PurgeDeliveries(now); err != nil {
+				log.Printf("Failed to purge webhook deliveries: %v", err)
+			} else if count > 0 {
+				log.Printf("Purged %d webhook deliveries", count)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enqueueWebhookEvent 在事务中为订阅了事件的 Webhook 创建投递记录，事务提交后由后台任务发送，
+// 事务回滚时事件随之丢弃
+func enqueueWebhookEvent(tx *gorm.DB, userID int64, event string, data interface{}) error {
+	var hooks []model.Webhook
+	if err := tx.Where("user_id = ? AND active = ?", userID, true).Find(&hooks).Error; err != nil {
+		return errors.New("查询 Webhook 失败")
+	}
+	var targets []*model.Webhook
+	for i := range hooks {
+		if webhookSubscribed(hooks[i].Events, event) {
+			targets = append(targets, &hooks[i])
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	payload, eventID, err := newWebhookPayload(event, data)
+	if err != nil {
+		return errors.New("生成 Webhook 事件失败")
+	}
+	deliveries := make([]model.WebhookDelivery, len(targets))
+	for i, hook := range targets {
+		deliveries[i] = newWebhookDelivery(hook, eventID, event, payload)
+	}
+	if err := tx.Create(&deliveries).Error; err != nil {
+		return errors.New("生成 Webhook 事件失败")
+	}
+	return nil
+}
+
+// enqueueTodoEvent 为事项的动态生成事件
+func enqueueTodoEvent(tx *gorm.DB, actorID int64, todo *model.Todo, action, source string, changes []model.FieldChange) error {
+	if changes == nil {
+		changes = []model.FieldChange{}
+	}
+	return enqueueWebhookEvent(tx, todo.UserID, "todo."+action, model.WebhookTodoEvent{
+		Todo:    webhookTodo(todo),
+		ActorID: actorID,
+		Source:  source,
+		Changes: changes,
+	})
+}
+
+// newWebhookPayload 生成带事件标识的请求体
+func newWebhookPayload(event string, data interface{}) (string, string, error) {
+	eventID, err := randomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+	payload, err := json.Marshal(model.WebhookPayload{
+		ID:        eventID,
+		Event:     event,
+		CreatedAt: time.Now().Unix(),
+		Data:      data,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return string(payload), eventID, nil
+}
+
+// newWebhookDelivery 创建立即发送的投递记录
+func newWebhookDelivery(hook *model.Webhook, eventID, event, payload string) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		WebhookID:     hook.ID,
+		UserID:        hook.UserID,
+		EventID:       eventID,
+		Event:         event,
+		Payload:       payload,
+		Status:        model.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+// webhookTodo 转换事件中的事项
+func webhookTodo(todo *model.Todo) model.WebhookTodo {
+	data := model.WebhookTodo{
+		ID:        todo.ID,
+		Title:     todo.Title,
+		Content:   todo.Content,
+		Status:    todo.Status,
+		Priority:  todo.Priority,
+		ProjectID: todo.ProjectID,
+		CreatedAt: todo.CreatedAt.Unix(),
+		UpdatedAt: todo.UpdatedAt.Unix(),
+	}
+	if todo.Deadline != nil {
+		deadline := todo.Deadline.Unix()
+		data.Deadline = &deadline
+	}
+	if todo.CompletedAt != nil {
+		completedAt := todo.CompletedAt.Unix()
+		data.CompletedAt = &completedAt
+	}
+	return data
+}
+
+// validateWebhookURL 校验回调地址，只接受 http 和 https，并拒绝 localhost 和内网地址。
+// 域名解析后的地址在发送时检查
+func validateWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > 2048 {
+		return "", errors.New("Webhook 地址无效")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", errors.New("Webhook 地址无效")
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", errors.New("Webhook 地址不能指向内网")
+	}
+	if ip := net.ParseIP(host); ip != nil && blockedWebhookIP(ip) {
+		return "", errors.New("Webhook 地址不能指向内网")
+	}
+	return raw, nil
+}
+
+// normalizeWebhookEvents 校验并去重订阅的事件，返回逗号分隔的字符串
+func normalizeWebhookEvents(events []string) (string, error) {
+	known := make(map[string]bool, len(model.WebhookEvents))
+	for _, event := range model.WebhookEvents {
+		known[event] = true
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if event == "" || seen[event] {
+			continue
+		}
+		if !known[event] && event != "*" && event != "todo.*" && event != "batch.*" {
+			return "", errors.New("不支持的事件: " + event)
+		}
+		seen[event] = true
+		result = append(result, event)
+	}
+	if len(result) == 0 {
+		return "", errors.New("至少需要订阅一个事件")
+	}
+	sort.Strings(result)
+	return strings.Join(result, ","), nil
+}
+
+// splitWebhookEvents 拆分订阅的事件
+func splitWebhookEvents(events string) []string {
+	if events == "" {
+		return []string{}
+	}
+	return strings.Split(events, ",")
+}
+
+// webhookSubscribed 判断订阅是否包含事件，支持 * 和 前缀.* 通配
+func webhookSubscribed(events, event string) bool {
+	for _, subscribed := range splitWebhookEvents(events) {
+		if subscribed == "*" || subscribed == event {
+			return true
+		}
+		if strings.HasSuffix(subscribed, ".*") && strings.HasPrefix(event, strings.TrimSuffix(subscribed, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookToResponse 转换 Webhook，withSecret 为 true 时包含签名密钥
+func webhookToResponse(hook *model.Webhook, withSecret bool) model.WebhookResponse {
+	resp := model.WebhookResponse{
+		ID:          hook.ID,
+		URL:         hook.URL,
+		Events:      splitWebhookEvents(hook.Events),
+		Description: hook.Description,
+		Active:      hook.Active,
+		CreatedAt:   hook.CreatedAt.Unix(),
+		UpdatedAt:   hook.UpdatedAt.Unix(),
+	}
+	if withSecret {
+		resp.Secret = hook.Secret
+	}
+	return resp
+}
+
+// webhookDeliveryToResponse 转换投递记录，withPayload 为 true 时包含请求体
+func webhookDeliveryToResponse(delivery *model.WebhookDelivery, withPayload bool) model.WebhookDeliveryResponse {
+	resp := model.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		Error:          delivery.Error,
+		Duration:       delivery.Duration,
+		CreatedAt:      delivery.CreatedAt.Unix(),
+	}
+	if delivery.Status == model.WebhookDeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt.Unix()
+		resp.NextAttemptAt = &nextAttemptAt
+	}
+	if delivery.DeliveredAt != nil {
+		deliveredAt := delivery.DeliveredAt.Unix()
+		resp.DeliveredAt = &deliveredAt
+	}
+	if withPayload && delivery.Payload != "" {
+		resp.Payload = json.RawMessage(delivery.Payload)
+	}
+	return resp
+}
+
+// truncateRunes 截断到指定的字符数
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}